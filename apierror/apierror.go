@@ -0,0 +1,89 @@
+// Package apierror defines a typed error shape shared by every handler and
+// middleware so clients can distinguish error classes programmatically
+// instead of pattern-matching on free-form strings.
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Code is a short, stable, machine-readable error identifier.
+type Code string
+
+const (
+	CodeNotFound      Code = "not_found"
+	CodeForbidden     Code = "forbidden"
+	CodeUnauthorized  Code = "unauthorized"
+	CodeInvalidInput  Code = "invalid_input"
+	CodeInternal      Code = "internal_error"
+)
+
+// APIError is the typed error returned by every handler and middleware.
+type APIError struct {
+	Code          Code           `json:"code"`
+	Status        int            `json:"-"`
+	Message       string         `json:"message"`
+	ApplicationID string         `json:"application_id,omitempty"`
+	Fields        map[string]any `json:"fields,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// sentinels are the canonical errors handlers build on with WithField/WithMessage.
+var (
+	ErrNotFound      = &APIError{Code: CodeNotFound, Status: http.StatusNotFound, Message: "resource not found"}
+	ErrForbidden     = &APIError{Code: CodeForbidden, Status: http.StatusForbidden, Message: "access denied"}
+	ErrInvalidInput  = &APIError{Code: CodeInvalidInput, Status: http.StatusBadRequest, Message: "invalid input"}
+	ErrUnauthorized  = &APIError{Code: CodeUnauthorized, Status: http.StatusUnauthorized, Message: "unauthorized"}
+	ErrInternal      = &APIError{Code: CodeInternal, Status: http.StatusInternalServerError, Message: "internal server error"}
+)
+
+// New clones a sentinel so callers can attach fields/messages without
+// mutating the shared sentinel value.
+func New(sentinel *APIError) *APIError {
+	clone := *sentinel
+	return &clone
+}
+
+// WithMessage overrides the human-readable message.
+func (e *APIError) WithMessage(msg string) *APIError {
+	e.Message = msg
+	return e
+}
+
+// WithField attaches a field to the error, e.g. the offending resource id.
+func (e *APIError) WithField(key string, value any) *APIError {
+	if e.Fields == nil {
+		e.Fields = make(map[string]any)
+	}
+	e.Fields[key] = value
+	return e
+}
+
+// WithApplicationID stamps the loan application the error relates to.
+func (e *APIError) WithApplicationID(id string) *APIError {
+	e.ApplicationID = id
+	return e
+}
+
+// WithRequestID stamps the request id so it round-trips in the response body.
+func (e *APIError) WithRequestID(id string) *APIError {
+	e.RequestID = id
+	return e
+}
+
+// From converts an arbitrary error into an *APIError, falling back to
+// ErrInternal when err isn't already typed.
+func From(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+	if ae, ok := err.(*APIError); ok {
+		return ae
+	}
+	return New(ErrInternal).WithMessage(err.Error())
+}
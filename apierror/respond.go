@@ -0,0 +1,69 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// problemJSON is the RFC 7807 representation of an APIError.
+type problemJSON struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	ApplicationID string         `json:"application_id,omitempty"`
+	Fields        map[string]any `json:"fields,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+}
+
+const problemContentType = "application/problem+json"
+
+// wantsProblemJSON checks whether the client negotiated RFC 7807 via Accept.
+func wantsProblemJSON(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), problemContentType)
+}
+
+// RespondError is the single helper every handler and middleware uses to
+// write an error response. It stamps the request id (set by RequestLogger),
+// sets the X-Request-ID header, and negotiates problem+json vs plain JSON.
+func RespondError(c *gin.Context, err error) {
+	ae := From(err)
+	if ae.RequestID == "" {
+		if rid, ok := c.Get("request_id"); ok {
+			if s, ok := rid.(string); ok {
+				ae.RequestID = s
+			}
+		}
+	}
+	if ae.RequestID != "" {
+		c.Header("X-Request-ID", ae.RequestID)
+	}
+
+	status := ae.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	if wantsProblemJSON(c) {
+		body, err := json.Marshal(problemJSON{
+			Type:          "about:blank",
+			Title:         string(ae.Code),
+			Status:        status,
+			Detail:        ae.Message,
+			ApplicationID: ae.ApplicationID,
+			Fields:        ae.Fields,
+			RequestID:     ae.RequestID,
+		})
+		if err != nil {
+			body = []byte(`{"title":"internal_error","status":500}`)
+		}
+		c.Data(status, problemContentType, body)
+		c.Abort()
+		return
+	}
+
+	c.AbortWithStatusJSON(status, ae)
+}
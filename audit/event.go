@@ -0,0 +1,46 @@
+// Package audit records an immutable trail of every state-changing action
+// for regulatory compliance: who did what to which resource, what changed,
+// and whether it succeeded. Entries are hash-chained so tampering with any
+// one of them invalidates every entry after it.
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is one audit entry. Before/After are opaque JSON snapshots of the
+// fields a handler changed (e.g. UpdateStatus's old/new status); most
+// actions only populate After, or neither.
+type Event struct {
+	ID         int64           `json:"id"`
+	Time       time.Time       `json:"time"`
+	Actor      string          `json:"actor"`
+	Role       string          `json:"role,omitempty"`
+	Action     string          `json:"action"`
+	Resource   string          `json:"resource"`
+	ResourceID string          `json:"resource_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Outcome    string          `json:"outcome"` // "success" | "failure"
+
+	// PrevHash/Hash form the tamper-evidence chain: Hash = sha256(PrevHash
+	// || canonical_json(Event minus ID/PrevHash/Hash)). Store implementations
+	// fill these in on Append; callers never set them.
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// Filter narrows Query the same way storage.Filter narrows
+// ListApplications: every non-zero field is ANDed together.
+type Filter struct {
+	Resource   string
+	ResourceID string
+	Actor      string
+	Action     string
+	Since      time.Time
+	Until      time.Time
+}
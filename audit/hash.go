@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// hashPayload is the fixed-field-order projection of an Event that gets
+// hashed - deliberately excluding ID/PrevHash/Hash themselves, which are
+// either assigned by the store (ID) or derived from this payload
+// (PrevHash/Hash).
+type hashPayload struct {
+	Time       string `json:"time"`
+	Actor      string `json:"actor"`
+	Role       string `json:"role"`
+	Action     string `json:"action"`
+	Resource   string `json:"resource"`
+	ResourceID string `json:"resource_id"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	RequestID  string `json:"request_id"`
+	Outcome    string `json:"outcome"`
+}
+
+// chainHash computes hash_n = sha256(hash_{n-1} || canonical_json(event_n))
+// for evt following prevHash in the chain. Time is truncated to microsecond
+// precision before hashing: Postgres's TIMESTAMPTZ column (migration 0005)
+// only preserves microseconds, so hashing at nanosecond precision would
+// make hash_n unreproducible from a row read back out of the PostgresStore.
+func chainHash(prevHash string, evt Event) (string, error) {
+	payload, err := json.Marshal(hashPayload{
+		Time:       evt.Time.UTC().Truncate(time.Microsecond).Format("2006-01-02T15:04:05.000000Z"),
+		Actor:      evt.Actor,
+		Role:       evt.Role,
+		Action:     evt.Action,
+		Resource:   evt.Resource,
+		ResourceID: evt.ResourceID,
+		Before:     string(evt.Before),
+		After:      string(evt.After),
+		IP:         evt.IP,
+		UserAgent:  evt.UserAgent,
+		RequestID:  evt.RequestID,
+		Outcome:    evt.Outcome,
+	})
+	if err != nil {
+		return "", fmt.Errorf("audit: marshal hash payload: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(prevHash), payload...))
+	return hex.EncodeToString(sum[:]), nil
+}
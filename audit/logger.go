@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger is what handlers call to record and read back audit events; it's
+// a thin facade over Store so swapping Postgres for the in-memory store
+// (tests, local dev) doesn't touch call sites.
+type Logger struct {
+	store Store
+}
+
+// NewLogger builds a Logger over store.
+func NewLogger(store Store) *Logger {
+	return &Logger{store: store}
+}
+
+// Record appends evt to the audit trail. A failure here is logged but not
+// propagated to the caller - losing an audit entry shouldn't fail the
+// business operation it was recording, though it is itself worth alerting
+// on operationally.
+func (l *Logger) Record(ctx context.Context, evt Event) {
+	if _, err := l.store.Append(ctx, evt); err != nil {
+		slog.ErrorContext(ctx, "audit: failed to record event", "actor", evt.Actor, "action", evt.Action, "resource", evt.Resource, "resource_id", evt.ResourceID, "error", err)
+	}
+}
+
+// Query returns every event matching filter, oldest first.
+func (l *Logger) Query(ctx context.Context, filter Filter) ([]Event, error) {
+	return l.store.Query(ctx, filter)
+}
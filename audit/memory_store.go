@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is the in-memory Store: used in tests and local dev without
+// Postgres, same role as storage.MemoryRepository.
+type MemoryStore struct {
+	mu       sync.Mutex
+	events   []Event
+	lastHash string
+}
+
+// NewMemoryStore returns an empty, ready-to-use MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, evt Event) (Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	evt.ID = int64(len(s.events)) + 1
+	evt.Time = time.Now()
+	evt.PrevHash = s.lastHash
+
+	hash, err := chainHash(s.lastHash, evt)
+	if err != nil {
+		return Event{}, err
+	}
+	evt.Hash = hash
+
+	s.events = append(s.events, evt)
+	s.lastHash = hash
+	return evt, nil
+}
+
+func (s *MemoryStore) Query(ctx context.Context, filter Filter) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Event
+	for _, evt := range s.events {
+		if matches(evt, filter) {
+			out = append(out, evt)
+		}
+	}
+	return out, nil
+}
+
+func matches(evt Event, filter Filter) bool {
+	if filter.Resource != "" && evt.Resource != filter.Resource {
+		return false
+	}
+	if filter.ResourceID != "" && evt.ResourceID != filter.ResourceID {
+		return false
+	}
+	if filter.Actor != "" && evt.Actor != filter.Actor {
+		return false
+	}
+	if filter.Action != "" && evt.Action != filter.Action {
+		return false
+	}
+	if !filter.Since.IsZero() && evt.Time.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && evt.Time.After(filter.Until) {
+		return false
+	}
+	return true
+}
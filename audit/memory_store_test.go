@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryStore_AppendChainsHashes(t *testing.T) {
+	store := NewMemoryStore()
+
+	first, err := store.Append(context.Background(), Event{Actor: "officer-1", Action: "create_loan_application", Resource: "loan", ResourceID: "1", Outcome: "success"})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if first.PrevHash != "" {
+		t.Fatalf("expected first event to have no prev hash, got %q", first.PrevHash)
+	}
+	if first.Hash == "" {
+		t.Fatalf("expected first event to have a hash")
+	}
+
+	second, err := store.Append(context.Background(), Event{Actor: "officer-1", Action: "update_status", Resource: "loan", ResourceID: "1", Outcome: "success"})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if second.PrevHash != first.Hash {
+		t.Fatalf("second event's prev hash %q should equal first event's hash %q", second.PrevHash, first.Hash)
+	}
+	if second.Hash == first.Hash {
+		t.Fatalf("distinct events must not hash to the same value")
+	}
+}
+
+func TestMemoryStore_QueryFiltersByResourceID(t *testing.T) {
+	store := NewMemoryStore()
+	store.Append(context.Background(), Event{Actor: "a", Action: "create_loan_application", Resource: "loan", ResourceID: "1", Outcome: "success"})
+	store.Append(context.Background(), Event{Actor: "a", Action: "create_loan_application", Resource: "loan", ResourceID: "2", Outcome: "success"})
+
+	events, err := store.Query(context.Background(), Filter{Resource: "loan", ResourceID: "1"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(events) != 1 || events[0].ResourceID != "1" {
+		t.Fatalf("expected exactly the event for resource 1, got %+v", events)
+	}
+}
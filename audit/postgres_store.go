@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore is the production Store. The audit_events table is
+// append-only by convention (Append is the only write this type performs)
+// and should also be enforced at the database level: the app's runtime
+// role should hold SELECT/INSERT on audit_events but not UPDATE/DELETE -
+// see storage/migrations/0005_audit_events.sql.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore wraps an already-connected, already-migrated pool.
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+// Append takes a transaction-scoped advisory lock before reading the last
+// hash, so concurrent writers (including the first ones into an empty
+// table) still produce a single, well-ordered hash chain instead of a race
+// on PrevHash. The advisory lock needs no UPDATE grant on audit_events and
+// is released on commit or rollback.
+func (s *PostgresStore) Append(ctx context.Context, evt Event) (Event, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Event{}, fmt.Errorf("audit: begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit_events_chain'))`); err != nil {
+		return Event{}, fmt.Errorf("audit: lock hash chain: %w", err)
+	}
+
+	var prevHash string
+	err = tx.QueryRow(ctx, `SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != pgx.ErrNoRows {
+		return Event{}, fmt.Errorf("audit: read last hash: %w", err)
+	}
+
+	evt.Time = time.Now()
+	evt.PrevHash = prevHash
+	hash, err := chainHash(prevHash, evt)
+	if err != nil {
+		return Event{}, err
+	}
+	evt.Hash = hash
+
+	row := tx.QueryRow(ctx, `
+		INSERT INTO audit_events
+			(actor, role, action, resource, resource_id, before_json, after_json, ip, user_agent, request_id, outcome, prev_hash, hash, "time")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id`,
+		evt.Actor, evt.Role, evt.Action, evt.Resource, evt.ResourceID, nullable(evt.Before), nullable(evt.After),
+		evt.IP, evt.UserAgent, evt.RequestID, evt.Outcome, evt.PrevHash, evt.Hash, evt.Time)
+	if err := row.Scan(&evt.ID); err != nil {
+		return Event{}, fmt.Errorf("audit: insert event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Event{}, fmt.Errorf("audit: commit: %w", err)
+	}
+	return evt, nil
+}
+
+func (s *PostgresStore) Query(ctx context.Context, filter Filter) ([]Event, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, "time", actor, role, action, resource, resource_id, before_json, after_json,
+		       ip, user_agent, request_id, outcome, prev_hash, hash
+		FROM audit_events
+		WHERE ($1 = '' OR resource = $1)
+		  AND ($2 = '' OR resource_id = $2)
+		  AND ($3 = '' OR actor = $3)
+		  AND ($4 = '' OR action = $4)
+		  AND ($5::timestamptz IS NULL OR "time" >= $5)
+		  AND ($6::timestamptz IS NULL OR "time" <= $6)
+		ORDER BY id`,
+		filter.Resource, filter.ResourceID, filter.Actor, filter.Action, nullableTime(filter.Since), nullableTime(filter.Until))
+	if err != nil {
+		return nil, fmt.Errorf("audit: query events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Event
+	for rows.Next() {
+		var evt Event
+		if err := rows.Scan(
+			&evt.ID, &evt.Time, &evt.Actor, &evt.Role, &evt.Action, &evt.Resource, &evt.ResourceID,
+			&evt.Before, &evt.After, &evt.IP, &evt.UserAgent, &evt.RequestID, &evt.Outcome, &evt.PrevHash, &evt.Hash,
+		); err != nil {
+			return nil, fmt.Errorf("audit: scan event: %w", err)
+		}
+		out = append(out, evt)
+	}
+	return out, nil
+}
+
+func nullable(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return raw
+}
+
+func nullableTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"loanapi/storage"
+)
+
+// TestPostgresStore_AppendChainsHashes runs the same contract as
+// TestMemoryStore_AppendChainsHashes against a real Postgres instance. It's
+// skipped unless TEST_DATABASE_URL is set, since this repo doesn't run a
+// database in CI by default.
+func TestPostgresStore_AppendChainsHashes(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping Postgres-backed audit store test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	assert.NoError(t, err)
+	defer pool.Close()
+
+	assert.NoError(t, storage.Migrate(ctx, pool))
+
+	store := NewPostgresStore(pool)
+	first, err := store.Append(ctx, Event{Actor: "officer-1", Action: "create_loan_application", Resource: "loan", ResourceID: "1", Outcome: "success"})
+	assert.NoError(t, err)
+	assert.Empty(t, first.PrevHash)
+	assert.NotEmpty(t, first.Hash)
+
+	second, err := store.Append(ctx, Event{Actor: "officer-1", Action: "update_status", Resource: "loan", ResourceID: "1", Outcome: "success"})
+	assert.NoError(t, err)
+	assert.Equal(t, first.Hash, second.PrevHash)
+
+	events, err := store.Query(ctx, Filter{Resource: "loan", ResourceID: "1"})
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+}
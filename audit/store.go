@@ -0,0 +1,11 @@
+package audit
+
+import "context"
+
+// Store is the append-only persistence boundary: there is deliberately no
+// Update or Delete. Append fills in ID, Time, PrevHash and Hash on evt and
+// returns the completed record.
+type Store interface {
+	Append(ctx context.Context, evt Event) (Event, error)
+	Query(ctx context.Context, filter Filter) ([]Event, error)
+}
@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// KeyManager wraps and unwraps data-encryption keys (DEKs) with a
+// key-encryption key (KEK) that never leaves the KMS/Vault/local store
+// behind it. EnvelopeCipher is KeyManager-agnostic: LocalFileKeyManager,
+// KMSKeyManager and VaultKeyManager are the only thing that differs
+// between dev and prod.
+type KeyManager interface {
+	// CurrentKeyID is the key new encryptions should be wrapped under.
+	CurrentKeyID() string
+	// WrapDEK wraps a freshly generated DEK under the KEK identified by keyID.
+	WrapDEK(ctx context.Context, keyID string, dek []byte) ([]byte, error)
+	// UnwrapDEK reverses WrapDEK.
+	UnwrapDEK(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// Rotate activates a new KEK version and returns its ID. Old versions
+	// must stay available to UnwrapDEK so existing ciphertexts keep
+	// decrypting until crypto.RewrapJob migrates them off.
+	Rotate(ctx context.Context) (keyID string, err error)
+}
+
+// EnvelopeCipher is the FieldCipher used in production: every Encrypt call
+// generates a fresh 256-bit DEK, seals the plaintext with it via
+// AES-256-GCM, then wraps the DEK itself with the KeyManager's current KEK.
+// Only the wrapped DEK and the field ciphertext are persisted - the raw DEK
+// never touches disk.
+type EnvelopeCipher struct {
+	keys KeyManager
+}
+
+// NewEnvelopeCipher builds an EnvelopeCipher backed by keys.
+func NewEnvelopeCipher(keys KeyManager) *EnvelopeCipher {
+	return &EnvelopeCipher{keys: keys}
+}
+
+// envelope is the wire format stored in place of the plaintext: the DEK
+// (wrapped by the KEK identified by KeyID) plus the field ciphertext under
+// that DEK. It's JSON-then-base64'd so it fits in a text column.
+type envelope struct {
+	KeyID      string `json:"key_id"`
+	WrappedDEK []byte `json:"wrapped_dek"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (e *EnvelopeCipher) Encrypt(ctx context.Context, plaintext string, aad []byte) (string, string, error) {
+	keyID := e.keys.CurrentKeyID()
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", "", fmt.Errorf("crypto: generate dek: %w", err)
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return "", "", fmt.Errorf("crypto: init dek cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", "", fmt.Errorf("crypto: init gcm: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", fmt.Errorf("crypto: generate nonce: %w", err)
+	}
+	ciphertext := gcm.Seal(nil, nonce, []byte(plaintext), aad)
+
+	wrapped, err := e.keys.WrapDEK(ctx, keyID, dek)
+	if err != nil {
+		return "", "", fmt.Errorf("crypto: wrap dek: %w", err)
+	}
+
+	raw, err := json.Marshal(envelope{KeyID: keyID, WrappedDEK: wrapped, Nonce: nonce, Ciphertext: ciphertext})
+	if err != nil {
+		return "", "", fmt.Errorf("crypto: marshal envelope: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), keyID, nil
+}
+
+func (e *EnvelopeCipher) Decrypt(ctx context.Context, ciphertext string, keyID string, aad []byte) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("crypto: decode envelope: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return "", fmt.Errorf("crypto: unmarshal envelope: %w", err)
+	}
+	if keyID != "" && keyID != env.KeyID {
+		return "", fmt.Errorf("crypto: key id mismatch: record says %q, envelope says %q", keyID, env.KeyID)
+	}
+
+	dek, err := e.keys.UnwrapDEK(ctx, env.KeyID, env.WrappedDEK)
+	if err != nil {
+		return "", fmt.Errorf("crypto: unwrap dek: %w", err)
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return "", fmt.Errorf("crypto: init dek cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("crypto: init gcm: %w", err)
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("crypto: decrypt field: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,76 @@
+package crypto
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func newTestKeyManager(t *testing.T) *LocalFileKeyManager {
+	t.Helper()
+	km, err := NewLocalFileKeyManager(filepath.Join(t.TempDir(), "kek.keys"))
+	if err != nil {
+		t.Fatalf("NewLocalFileKeyManager: %v", err)
+	}
+	return km
+}
+
+func TestEnvelopeCipher_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	km := newTestKeyManager(t)
+	c := NewEnvelopeCipher(km)
+
+	ciphertext, keyID, err := c.Encrypt(ctx, "123-45-6789", SSNAAD(1))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if ciphertext == "123-45-6789" {
+		t.Fatalf("ciphertext must not equal plaintext")
+	}
+
+	plaintext, err := c.Decrypt(ctx, ciphertext, keyID, SSNAAD(1))
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if plaintext != "123-45-6789" {
+		t.Fatalf("got %q, want original plaintext", plaintext)
+	}
+}
+
+func TestEnvelopeCipher_WrongAADFailsToDecrypt(t *testing.T) {
+	ctx := context.Background()
+	km := newTestKeyManager(t)
+	c := NewEnvelopeCipher(km)
+
+	ciphertext, keyID, err := c.Encrypt(ctx, "123-45-6789", SSNAAD(1))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := c.Decrypt(ctx, ciphertext, keyID, SSNAAD(2)); err == nil {
+		t.Fatalf("expected decrypt under a different application's AAD to fail")
+	}
+}
+
+func TestEnvelopeCipher_DecryptsAfterKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	km := newTestKeyManager(t)
+	c := NewEnvelopeCipher(km)
+
+	ciphertext, keyID, err := c.Encrypt(ctx, "123-45-6789", SSNAAD(1))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := km.Rotate(ctx); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	plaintext, err := c.Decrypt(ctx, ciphertext, keyID, SSNAAD(1))
+	if err != nil {
+		t.Fatalf("Decrypt after rotation: %v", err)
+	}
+	if plaintext != "123-45-6789" {
+		t.Fatalf("got %q, want original plaintext", plaintext)
+	}
+}
@@ -0,0 +1,26 @@
+// Package crypto provides field-level envelope encryption for sensitive
+// data - currently just ApplicantSSN - so plaintext never reaches the
+// repository or an API response unless the caller is explicitly authorized
+// to see it (the loan:read-pii scope).
+package crypto
+
+import (
+	"context"
+	"strconv"
+)
+
+// FieldCipher encrypts and decrypts individual field values. aad
+// (additional authenticated data) binds a ciphertext to the record it
+// belongs to - callers pass something like SSNAAD(applicationID) - so a
+// ciphertext can't be copied between records even by someone who can read
+// the datastore directly.
+type FieldCipher interface {
+	Encrypt(ctx context.Context, plaintext string, aad []byte) (ciphertext string, keyID string, err error)
+	Decrypt(ctx context.Context, ciphertext string, keyID string, aad []byte) (plaintext string, err error)
+}
+
+// SSNAAD returns the additional authenticated data bound to an
+// application's encrypted SSN.
+func SSNAAD(applicationID int) []byte {
+	return []byte("loan_application_ssn:" + strconv.Itoa(applicationID))
+}
@@ -0,0 +1,174 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LocalFileKeyManager is the dev/local KeyManager: each KEK version is a
+// 32-byte AES key, one "keyID:hexkey" line per version in a plain file.
+// It exists so the rest of the stack can run without AWS or Vault
+// configured; production should use KMSKeyManager or VaultKeyManager so
+// KEK material never sits unencrypted on disk.
+type LocalFileKeyManager struct {
+	mu      sync.RWMutex
+	path    string
+	keys    map[string][]byte
+	current string
+}
+
+// NewLocalFileKeyManager loads path (creating it with a fresh key if it
+// doesn't exist yet).
+func NewLocalFileKeyManager(path string) (*LocalFileKeyManager, error) {
+	m := &LocalFileKeyManager{path: path, keys: make(map[string][]byte)}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	if m.current == "" {
+		if _, err := m.Rotate(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *LocalFileKeyManager) load() error {
+	raw, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("crypto: read key file: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+		if line == "" {
+			continue
+		}
+		if id, ok := cutPrefix(line, "current:"); ok {
+			m.current = id
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("crypto: malformed key file line %q", line)
+		}
+		key, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return fmt.Errorf("crypto: decode key %s: %w", parts[0], err)
+		}
+		m.keys[parts[0]] = key
+	}
+	return nil
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
+
+// persist writes every known key version plus an explicit "current:<id>"
+// marker line recording which one is active. The marker is required
+// because the key lines come from ranging m.keys (random order), so which
+// one physically ends up last in the file says nothing about which
+// version Rotate most recently activated.
+func (m *LocalFileKeyManager) persist() error {
+	var sb strings.Builder
+	for id, key := range m.keys {
+		sb.WriteString(id)
+		sb.WriteByte(':')
+		sb.WriteString(hex.EncodeToString(key))
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("current:")
+	sb.WriteString(m.current)
+	sb.WriteByte('\n')
+	return os.WriteFile(m.path, []byte(sb.String()), 0o600)
+}
+
+func (m *LocalFileKeyManager) CurrentKeyID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+func (m *LocalFileKeyManager) WrapDEK(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	m.mu.RLock()
+	kek, ok := m.keys[keyID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("crypto: unknown key id %q", keyID)
+	}
+	return seal(kek, dek)
+}
+
+func (m *LocalFileKeyManager) UnwrapDEK(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	m.mu.RLock()
+	kek, ok := m.keys[keyID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("crypto: unknown key id %q", keyID)
+	}
+	return open(kek, wrapped)
+}
+
+// Rotate generates a new KEK version and activates it; older versions stay
+// in the file so UnwrapDEK keeps working for records not yet rewrapped.
+func (m *LocalFileKeyManager) Rotate(ctx context.Context) (string, error) {
+	kek := make([]byte, 32)
+	if _, err := rand.Read(kek); err != nil {
+		return "", fmt.Errorf("crypto: generate kek: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := fmt.Sprintf("local-v%d", len(m.keys)+1)
+	m.keys[id] = kek
+	m.current = id
+	if err := m.persist(); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// seal/open are the raw AES-256-GCM primitives both WrapDEK/UnwrapDEK use;
+// the wrapped form is nonce||ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("crypto: wrapped key too short")
+	}
+	nonce, ciphertext := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
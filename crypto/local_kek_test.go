@@ -0,0 +1,41 @@
+package crypto
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// TestLocalFileKeyManager_CurrentSurvivesReload guards against regressing to
+// "whichever key line the map range wrote last" by rotating twice and
+// confirming a freshly loaded manager still reports the most recently
+// rotated key as current, not some other version.
+func TestLocalFileKeyManager_CurrentSurvivesReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kek.keys")
+	ctx := context.Background()
+
+	km, err := NewLocalFileKeyManager(path)
+	if err != nil {
+		t.Fatalf("NewLocalFileKeyManager: %v", err)
+	}
+	first := km.CurrentKeyID()
+
+	var last string
+	for i := 0; i < 5; i++ {
+		last, err = km.Rotate(ctx)
+		if err != nil {
+			t.Fatalf("Rotate: %v", err)
+		}
+	}
+	if last == first {
+		t.Fatalf("expected Rotate to activate a new key id each time")
+	}
+
+	reloaded, err := NewLocalFileKeyManager(path)
+	if err != nil {
+		t.Fatalf("NewLocalFileKeyManager (reload): %v", err)
+	}
+	if got := reloaded.CurrentKeyID(); got != last {
+		t.Fatalf("CurrentKeyID after reload = %q, want %q (most recently rotated)", got, last)
+	}
+}
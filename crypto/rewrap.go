@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"loanapi/storage"
+)
+
+// RewrapJob walks every application whose SSN is sealed under a key other
+// than the cipher's current one and re-encrypts it under the current key.
+// Run it after KeyManager.Rotate so retired KEK versions can eventually be
+// disabled without losing access to older records.
+type RewrapJob struct {
+	repo   storage.Repository
+	cipher FieldCipher
+	keys   KeyManager
+}
+
+// NewRewrapJob builds a RewrapJob over repo using cipher/keys to decrypt
+// and re-encrypt.
+func NewRewrapJob(repo storage.Repository, cipher FieldCipher, keys KeyManager) *RewrapJob {
+	return &RewrapJob{repo: repo, cipher: cipher, keys: keys}
+}
+
+// Run re-encrypts every record not already sealed under the current key
+// ID, logging and skipping (rather than aborting) any single record that
+// fails so one bad row doesn't block the rest from migrating.
+func (j *RewrapJob) Run(ctx context.Context) error {
+	current := j.keys.CurrentKeyID()
+	keyIDs, err := j.repo.ListSSNKeyIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("crypto: list ssn key ids: %w", err)
+	}
+
+	rewrapped := 0
+	for id, keyID := range keyIDs {
+		if keyID == current {
+			continue
+		}
+		if err := j.rewrapOne(ctx, id); err != nil {
+			slog.ErrorContext(ctx, "rewrap: failed to rewrap application", "application_id", id, "error", err)
+			continue
+		}
+		rewrapped++
+	}
+	slog.InfoContext(ctx, "rewrap: completed", "rewrapped", rewrapped, "total", len(keyIDs), "key_id", current)
+	return nil
+}
+
+func (j *RewrapJob) rewrapOne(ctx context.Context, applicationID int) error {
+	app, err := j.repo.GetApplication(ctx, applicationID)
+	if err != nil {
+		return fmt.Errorf("get application: %w", err)
+	}
+	plaintext, err := j.cipher.Decrypt(ctx, app.ApplicantSSN, app.SSNKeyID, SSNAAD(applicationID))
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+	ciphertext, keyID, err := j.cipher.Encrypt(ctx, plaintext, SSNAAD(applicationID))
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+	if err := j.repo.RewrapSSN(ctx, applicationID, ciphertext, keyID); err != nil {
+		return fmt.Errorf("persist: %w", err)
+	}
+	return nil
+}
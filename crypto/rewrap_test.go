@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"loanapi/storage"
+)
+
+func TestRewrapJob_RewrapsRecordsOnOldKey(t *testing.T) {
+	ctx := context.Background()
+	km := newTestKeyManager(t)
+	c := NewEnvelopeCipher(km)
+	repo := storage.NewMemoryRepository()
+
+	app, err := repo.CreateApplication(ctx, storage.LoanApplication{ApplicantName: "Jane Smith"})
+	if err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	ciphertext, keyID, err := c.Encrypt(ctx, "123-45-6789", SSNAAD(app.ID))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if err := repo.RewrapSSN(ctx, app.ID, ciphertext, keyID); err != nil {
+		t.Fatalf("seed RewrapSSN: %v", err)
+	}
+
+	newKeyID, err := km.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	job := NewRewrapJob(repo, c, km)
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	got, err := repo.GetApplication(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("GetApplication: %v", err)
+	}
+	if got.SSNKeyID != newKeyID {
+		t.Fatalf("got key id %q, want %q", got.SSNKeyID, newKeyID)
+	}
+
+	plaintext, err := c.Decrypt(ctx, got.ApplicantSSN, got.SSNKeyID, SSNAAD(app.ID))
+	if err != nil {
+		t.Fatalf("Decrypt rewrapped ciphertext: %v", err)
+	}
+	if plaintext != "123-45-6789" {
+		t.Fatalf("got %q, want original plaintext", plaintext)
+	}
+}
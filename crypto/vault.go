@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// VaultKeyManager wraps/unwraps DEKs through Vault's Transit secrets
+// engine. keyName is the Transit key name (e.g. "loan-ssn"); like KMS,
+// Vault tracks key versions internally, so keyID here is that key name
+// rather than a specific version.
+type VaultKeyManager struct {
+	client  *vaultapi.Client
+	keyName string
+}
+
+// NewVaultKeyManager wraps an already-configured Vault client pointed at
+// the Transit key keyName.
+func NewVaultKeyManager(client *vaultapi.Client, keyName string) *VaultKeyManager {
+	return &VaultKeyManager{client: client, keyName: keyName}
+}
+
+func (m *VaultKeyManager) CurrentKeyID() string {
+	return m.keyName
+}
+
+func (m *VaultKeyManager) WrapDEK(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	secret, err := m.client.Logical().WriteWithContext(ctx, "transit/encrypt/"+keyID, map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(dek),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("crypto: vault encrypt: %w", err)
+	}
+	ciphertext, _ := secret.Data["ciphertext"].(string)
+	return []byte(ciphertext), nil
+}
+
+func (m *VaultKeyManager) UnwrapDEK(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	secret, err := m.client.Logical().WriteWithContext(ctx, "transit/decrypt/"+keyID, map[string]interface{}{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("crypto: vault decrypt: %w", err)
+	}
+	encoded, _ := secret.Data["plaintext"].(string)
+	dek, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: decode vault plaintext: %w", err)
+	}
+	return dek, nil
+}
+
+// Rotate asks Vault to rotate the Transit key itself; Transit keeps every
+// past version decryptable, so UnwrapDEK (which always passes Vault the
+// ciphertext's own embedded version prefix) keeps working unchanged.
+func (m *VaultKeyManager) Rotate(ctx context.Context) (string, error) {
+	if _, err := m.client.Logical().WriteWithContext(ctx, "transit/keys/"+m.keyName+"/rotate", nil); err != nil {
+		return "", fmt.Errorf("crypto: vault rotate: %w", err)
+	}
+	return m.keyName, nil
+}
@@ -0,0 +1,37 @@
+// Package extraction replaces the old "sleep 3 seconds and call it
+// processed" stub with a real worker pool that pulls text and loan-related
+// fields out of uploaded PDFs, retrying transient failures and parking
+// permanent ones in a dead letter queue.
+package extraction
+
+import "context"
+
+// Fields are the loan-related values pulled out of a document. Any of them
+// may be empty if the extractor couldn't find a confident match.
+type Fields struct {
+	PageCount int    `json:"page_count"`
+	SSN       string `json:"ssn,omitempty"`
+	Income    string `json:"income,omitempty"`
+	Employer  string `json:"employer,omitempty"`
+}
+
+// StoredFields is the JSON shape persisted in
+// DocumentJobRecord.ExtractedFields. It mirrors Fields except the SSN is
+// sealed behind SSNEncrypted/SSNKeyID instead of carried as plaintext - the
+// same envelope encryption CreateLoanApplication uses for ApplicantSSN - so
+// a raw PDF scrape can't bypass that guarantee just by landing in the jobs
+// table instead of the applications one.
+type StoredFields struct {
+	PageCount    int    `json:"page_count"`
+	SSNEncrypted string `json:"ssn_encrypted,omitempty"`
+	SSNKeyID     string `json:"ssn_key_id,omitempty"`
+	Income       string `json:"income,omitempty"`
+	Employer     string `json:"employer,omitempty"`
+}
+
+// DocumentExtractor pulls text and structured fields out of a single file.
+// The default implementation (pdf_extractor.go) handles PDFs; tests swap in
+// a fake that returns canned fields without touching disk.
+type DocumentExtractor interface {
+	Extract(ctx context.Context, filePath string) (Fields, error)
+}
@@ -0,0 +1,60 @@
+package extraction
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+var (
+	ssnPattern      = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	incomePattern   = regexp.MustCompile(`(?i)(?:annual income|income)[:\s]*\$?([\d,]+(?:\.\d{2})?)`)
+	employerPattern = regexp.MustCompile(`(?i)employer[:\s]*([A-Za-z0-9&.,' -]{2,60})`)
+)
+
+// PDFExtractor is the default DocumentExtractor, pulling raw text out of a
+// PDF with ledongthuc/pdf and scanning it for SSN/income/employer patterns.
+type PDFExtractor struct{}
+
+// NewPDFExtractor returns the default, stateless PDF extractor.
+func NewPDFExtractor() *PDFExtractor {
+	return &PDFExtractor{}
+}
+
+func (e *PDFExtractor) Extract(ctx context.Context, filePath string) (Fields, error) {
+	f, r, err := pdf.Open(filePath)
+	if err != nil {
+		return Fields{}, fmt.Errorf("extraction: open %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	pages := r.NumPage()
+	for i := 1; i <= pages; i++ {
+		if err := ctx.Err(); err != nil {
+			return Fields{}, err
+		}
+		text, err := r.Page(i).GetPlainText(nil)
+		if err != nil {
+			continue // a single unreadable page shouldn't fail the whole document
+		}
+		buf.WriteString(text)
+	}
+
+	content := buf.String()
+	fields := Fields{PageCount: pages}
+	if m := ssnPattern.FindString(content); m != "" {
+		fields.SSN = m
+	}
+	if m := incomePattern.FindStringSubmatch(content); len(m) > 1 {
+		fields.Income = strings.TrimSpace(m[1])
+	}
+	if m := employerPattern.FindStringSubmatch(content); len(m) > 1 {
+		fields.Employer = strings.TrimSpace(m[1])
+	}
+	return fields, nil
+}
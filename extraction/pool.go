@@ -0,0 +1,254 @@
+package extraction
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"loanapi/crypto"
+	"loanapi/metrics"
+	"loanapi/storage"
+	"loanapi/tracing"
+)
+
+const maxAttempts = 3
+
+// retryBackoff computes the delay before retrying a failed job; tests
+// override it to avoid waiting on real wall-clock time.
+var retryBackoff = func(attempt int) time.Duration {
+	return time.Duration(1<<uint(attempt)) * time.Second // 2s after attempt 1, 4s after attempt 2; attempt 3 dead-letters
+}
+
+// Job is what UploadDocuments hands to the pool after it has created the
+// DocumentJobRecord in storage. TraceParent is the W3C traceparent header
+// from the request that queued it, so the worker's span can be a child of
+// the request's even though processing happens on a different goroutine.
+type Job struct {
+	JobID         int
+	ApplicationID int
+	FilePath      string
+	TraceParent   string
+}
+
+// Publisher is notified of every job state transition so callers can push
+// it on to interested listeners (e.g. the streaming package's SSE hub).
+// Pool only needs this narrow signature, so any type with a matching
+// Publish method satisfies it without either package importing the other.
+type Publisher interface {
+	Publish(applicationID int, eventType, status string, progress int)
+}
+
+// Pool is a fixed-size worker pool processing document extraction jobs. It
+// persists every state transition through repo (JobRepository) so a
+// restart can see what was in flight, retries failures with exponential
+// backoff up to maxAttempts, and dead-letters anything that still fails
+// after that.
+type Pool struct {
+	workers   int
+	repo      storage.Repository
+	extractor DocumentExtractor
+	publisher Publisher
+	ssnCipher crypto.FieldCipher
+	jobs      chan Job
+	wg        sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Option customizes a Pool at construction time.
+type Option func(*Pool)
+
+// WithPublisher makes the pool notify p of every job state transition.
+func WithPublisher(p Publisher) Option {
+	return func(pool *Pool) {
+		pool.publisher = p
+	}
+}
+
+// WithSSNEncryptor seals any SSN the extractor finds in a document before
+// it's persisted, using the same envelope encryption CreateLoanApplication
+// uses for ApplicantSSN. Without it, a found SSN is dropped rather than
+// stored in plaintext (see process).
+func WithSSNEncryptor(cipher crypto.FieldCipher) Option {
+	return func(pool *Pool) {
+		pool.ssnCipher = cipher
+	}
+}
+
+// NewPool builds a pool with the given number of workers. Call Start to
+// begin processing and Shutdown to drain in-flight jobs before exiting.
+func NewPool(workers int, repo storage.Repository, extractor DocumentExtractor, opts ...Option) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &Pool{
+		workers:   workers,
+		repo:      repo,
+		extractor: extractor,
+		jobs:      make(chan Job, 100),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// publish notifies the configured Publisher, if any, of a state change.
+func (p *Pool) publish(applicationID int, eventType, status string, progress int) {
+	if p.publisher == nil {
+		return
+	}
+	p.publisher.Publish(applicationID, eventType, status, progress)
+}
+
+// Submit queues a job for processing. It blocks if the queue is full, the
+// same backpressure the old unbuffered channel gave UploadDocuments. It's a
+// no-op once Shutdown has been called, since p.jobs is closed at that point
+// and sending on it would panic - a retry scheduled by handleFailure can
+// otherwise land after shutdown has already begun.
+func (p *Pool) Submit(job Job) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	metrics.DocumentQueueDepth.Inc()
+	p.jobs <- job
+}
+
+// Start launches the worker goroutines. They run until Shutdown closes the
+// job channel and it is drained; cancelling ctx does not stop them, it only
+// reaches each job's processing.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.worker(ctx, i)
+	}
+}
+
+// Shutdown stops accepting new jobs and blocks until every in-flight job
+// (and anything already queued) has finished, or ctx is done.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	close(p.jobs)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) worker(ctx context.Context, id int) {
+	defer p.wg.Done()
+	// Dequeue is driven solely by p.jobs closing (Shutdown's job): ranging
+	// here, rather than also selecting on ctx.Done(), guarantees queued
+	// jobs are drained rather than dropped when the app context is
+	// cancelled during shutdown. ctx is still threaded into process so
+	// in-flight extraction work can be aborted promptly.
+	for job := range p.jobs {
+		metrics.DocumentQueueDepth.Dec()
+		p.process(ctx, job)
+	}
+}
+
+func (p *Pool) process(ctx context.Context, job Job) {
+	ctx = tracing.Propagator().Extract(ctx, propagation.MapCarrier{"traceparent": job.TraceParent})
+	ctx, span := tracing.Tracer().Start(ctx, "extraction.process_document",
+		trace.WithAttributes(attribute.Int("application_id", job.ApplicationID), attribute.Int("job_id", job.JobID)))
+	defer span.End()
+
+	start := time.Now()
+	outcome := "completed"
+	defer func() {
+		metrics.ProcessDocumentDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	}()
+
+	if err := p.repo.MarkJobProcessing(ctx, job.JobID); err != nil {
+		slog.ErrorContext(ctx, "extract: failed to mark processing", "job_id", job.JobID, "error", err)
+	}
+	_ = p.repo.SetProcessingStatus(ctx, job.ApplicationID, "processing")
+	p.publish(job.ApplicationID, "status", "processing", 0)
+
+	fields, err := p.extractor.Extract(ctx, job.FilePath)
+	if err != nil {
+		outcome = p.handleFailure(ctx, job, err)
+		return
+	}
+	p.publish(job.ApplicationID, "progress", "processing", 50)
+
+	stored := StoredFields{PageCount: fields.PageCount, Income: fields.Income, Employer: fields.Employer}
+	if fields.SSN != "" {
+		if p.ssnCipher == nil {
+			slog.WarnContext(ctx, "extract: no SSN cipher configured, dropping extracted SSN", "job_id", job.JobID)
+		} else {
+			ciphertext, keyID, err := p.ssnCipher.Encrypt(ctx, fields.SSN, crypto.SSNAAD(job.ApplicationID))
+			if err != nil {
+				outcome = p.handleFailure(ctx, job, err)
+				return
+			}
+			stored.SSNEncrypted = ciphertext
+			stored.SSNKeyID = keyID
+		}
+	}
+
+	payload, err := json.Marshal(stored)
+	if err != nil {
+		outcome = p.handleFailure(ctx, job, err)
+		return
+	}
+	if err := p.repo.MarkJobCompleted(ctx, job.JobID, string(payload)); err != nil {
+		slog.ErrorContext(ctx, "extract: failed to mark completed", "job_id", job.JobID, "error", err)
+	}
+	_ = p.repo.SetProcessingStatus(ctx, job.ApplicationID, "completed")
+	slog.InfoContext(ctx, "extract: completed", "job_id", job.JobID, "application_id", job.ApplicationID)
+	p.publish(job.ApplicationID, "result", "completed", 100)
+}
+
+// handleFailure records the failed attempt, retrying with backoff or
+// dead-lettering once maxAttempts is exhausted. It returns the outcome
+// label process should record the attempt's duration under.
+func (p *Pool) handleFailure(ctx context.Context, job Job, cause error) string {
+	attempts, err := p.repo.MarkJobFailed(ctx, job.JobID, cause.Error())
+	if err != nil {
+		slog.ErrorContext(ctx, "extract: failed to record failure", "job_id", job.JobID, "error", err)
+	}
+
+	if attempts >= maxAttempts {
+		if err := p.repo.MoveToDeadLetter(ctx, job.JobID, cause.Error()); err != nil {
+			slog.ErrorContext(ctx, "extract: failed to dead-letter", "job_id", job.JobID, "error", err)
+		}
+		_ = p.repo.SetProcessingStatus(ctx, job.ApplicationID, "failed")
+		slog.ErrorContext(ctx, "extract: dead-lettered", "job_id", job.JobID, "application_id", job.ApplicationID, "attempts", attempts, "cause", cause)
+		p.publish(job.ApplicationID, "result", "failed", 0)
+		return "dead_lettered"
+	}
+
+	p.publish(job.ApplicationID, "status", "retrying", 0)
+
+	backoff := retryBackoff(attempts)
+	slog.WarnContext(ctx, "extract: attempt failed, retrying", "job_id", job.JobID, "application_id", job.ApplicationID, "attempts", attempts, "backoff", backoff, "cause", cause)
+	go func() {
+		select {
+		case <-time.After(backoff):
+			p.Submit(job)
+		case <-ctx.Done():
+		}
+	}()
+	return "retrying"
+}
@@ -0,0 +1,167 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"loanapi/storage"
+)
+
+// fakeCipher is a stand-in FieldCipher: it doesn't obscure anything, but it
+// lets tests assert the plaintext SSN never reaches storage.MarkJobCompleted
+// without pulling in the real crypto package's key management.
+type fakeCipher struct{}
+
+func (fakeCipher) Encrypt(ctx context.Context, plaintext string, aad []byte) (string, string, error) {
+	return "enc:" + plaintext, "test-key", nil
+}
+
+func (fakeCipher) Decrypt(ctx context.Context, ciphertext string, keyID string, aad []byte) (string, error) {
+	return strings.TrimPrefix(ciphertext, "enc:"), nil
+}
+
+// fakeExtractor lets tests control whether Extract succeeds without
+// touching a real PDF on disk.
+type fakeExtractor struct {
+	failTimes int
+	calls     int
+	fields    Fields
+}
+
+func (f *fakeExtractor) Extract(ctx context.Context, filePath string) (Fields, error) {
+	f.calls++
+	if f.calls <= f.failTimes {
+		return Fields{}, errors.New("boom")
+	}
+	return f.fields, nil
+}
+
+func TestPool_ProcessesJobSuccessfully(t *testing.T) {
+	repo := storage.NewMemoryRepository()
+	ctx := context.Background()
+	app, _ := repo.CreateApplication(ctx, storage.LoanApplication{ApplicantName: "Jane Smith"})
+	job, _ := repo.CreateDocumentJob(ctx, app.ID, "uploads/1_doc.pdf")
+
+	pool := NewPool(1, repo, &fakeExtractor{fields: Fields{PageCount: 2, SSN: "123-45-6789"}})
+	poolCtx, cancel := context.WithCancel(ctx)
+	pool.Start(poolCtx)
+	pool.Submit(Job{JobID: job.ID, ApplicationID: app.ID, FilePath: job.FilePath})
+
+	assert.Eventually(t, func() bool {
+		got, err := repo.GetDocumentJob(ctx, job.ID)
+		return err == nil && got.Status == storage.JobCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	_ = pool.Shutdown(context.Background())
+}
+
+func TestPool_EncryptsExtractedSSNBeforePersisting(t *testing.T) {
+	repo := storage.NewMemoryRepository()
+	ctx := context.Background()
+	app, _ := repo.CreateApplication(ctx, storage.LoanApplication{ApplicantName: "Jane Smith"})
+	job, _ := repo.CreateDocumentJob(ctx, app.ID, "uploads/1_doc.pdf")
+
+	pool := NewPool(1, repo, &fakeExtractor{fields: Fields{PageCount: 2, SSN: "123-45-6789"}}, WithSSNEncryptor(fakeCipher{}))
+	poolCtx, cancel := context.WithCancel(ctx)
+	pool.Start(poolCtx)
+	pool.Submit(Job{JobID: job.ID, ApplicationID: app.ID, FilePath: job.FilePath})
+
+	assert.Eventually(t, func() bool {
+		got, err := repo.GetDocumentJob(ctx, job.ID)
+		return err == nil && got.Status == storage.JobCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	_ = pool.Shutdown(context.Background())
+
+	got, err := repo.GetDocumentJob(ctx, job.ID)
+	assert.Nil(t, err)
+	assert.NotContains(t, got.ExtractedFields, "123-45-6789")
+	assert.Contains(t, got.ExtractedFields, "ssn_encrypted")
+}
+
+// fakePublisher records every event handed to it so tests can assert on
+// the sequence of state transitions the pool published.
+type fakePublisher struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (f *fakePublisher) Publish(applicationID int, eventType, status string, progress int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, eventType+":"+status)
+}
+
+func (f *fakePublisher) snapshot() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]string, len(f.events))
+	copy(out, f.events)
+	return out
+}
+
+func TestPool_PublishesStateTransitions(t *testing.T) {
+	repo := storage.NewMemoryRepository()
+	ctx := context.Background()
+	app, _ := repo.CreateApplication(ctx, storage.LoanApplication{ApplicantName: "Jane Smith"})
+	job, _ := repo.CreateDocumentJob(ctx, app.ID, "uploads/1_doc.pdf")
+
+	publisher := &fakePublisher{}
+	pool := NewPool(1, repo, &fakeExtractor{fields: Fields{PageCount: 1}}, WithPublisher(publisher))
+	poolCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pool.Start(poolCtx)
+	pool.Submit(Job{JobID: job.ID, ApplicationID: app.ID, FilePath: job.FilePath})
+
+	assert.Eventually(t, func() bool {
+		got, err := repo.GetDocumentJob(ctx, job.ID)
+		return err == nil && got.Status == storage.JobCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	events := publisher.snapshot()
+	assert.Contains(t, events, "status:processing")
+	assert.Contains(t, events, "result:completed")
+}
+
+func TestPool_DeadLettersAfterMaxAttempts(t *testing.T) {
+	originalBackoff := retryBackoff
+	retryBackoff = func(attempt int) time.Duration { return time.Millisecond }
+	defer func() { retryBackoff = originalBackoff }()
+
+	tests := []struct {
+		name      string
+		failTimes int
+		want      storage.JobStatus
+	}{
+		{name: "always fails, goes to dead letter", failTimes: maxAttempts, want: storage.JobDeadLettered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := storage.NewMemoryRepository()
+			ctx := context.Background()
+			app, _ := repo.CreateApplication(ctx, storage.LoanApplication{ApplicantName: "Jane Smith"})
+			job, _ := repo.CreateDocumentJob(ctx, app.ID, "uploads/1_doc.pdf")
+
+			extractor := &fakeExtractor{failTimes: tt.failTimes}
+			pool := NewPool(1, repo, extractor)
+			poolCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			pool.Start(poolCtx)
+			pool.Submit(Job{JobID: job.ID, ApplicationID: app.ID, FilePath: job.FilePath})
+
+			assert.Eventually(t, func() bool {
+				got, err := repo.GetDocumentJob(ctx, job.ID)
+				return err == nil && got.Status == tt.want
+			}, 2*time.Second, 10*time.Millisecond)
+		})
+	}
+}
@@ -1,84 +1,277 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"loanapi/apierror"
+	"loanapi/audit"
+	"loanapi/crypto"
+	"loanapi/extraction"
+	"loanapi/metrics"
+	"loanapi/oauth2server"
+	"loanapi/storage"
+	"loanapi/streaming"
+	"loanapi/tracing"
 )
 
-// LoanApplication
-type LoanApplication struct {
-	ID                int        `json:"id"`
-	ApplicantName     string     `json:"applicant_name"`
-	ApplicantSSN      string     `json:"applicant_ssn"`
-	MaskedSSN         string     `json:"masked_ssn,omitempty"`
-	LoanAmount        float64    `json:"loan_amount"`
-	LoanPurpose       string     `json:"loan_purpose"`
-	AnnualIncome      float64    `json:"annual_income"`
-	CreditScore       int        `json:"credit_score"`
-	Status            string     `json:"status"`
-	SubmittedAt       time.Time  `json:"submitted_at"`
-	ProcessedAt       *time.Time `json:"processed_at,omitempty"`
-	DocumentsUploaded []string   `json:"documents_uploaded"`
-}
+// LoanApplication is an alias to the repository's type so handlers didn't
+// need to change at every call site when persistence moved out of main.go.
+type LoanApplication = storage.LoanApplication
 
-var loanApplications = make(map[int]LoanApplication)
-var currentID = 1
-var jwtSecret = []byte("supersecretkey")
+// repo is the persistence boundary every handler goes through. It's either
+// the in-memory implementation (default, tests) or Postgres (when
+// DATABASE_URL is set) - see newRepository below.
+var repo storage.Repository
 
-type DocumentJob struct {
-	AppID    int
-	FilePath string
-}
+// extractionPool replaces the old documentQueue channel + single goroutine;
+// it's a fixed-size worker pool with retries and a dead letter queue, see
+// package extraction.
+var extractionPool *extraction.Pool
+
+// eventHub fans out loan application processing events to SSE/WS clients;
+// extractionPool publishes to it on every job state transition.
+var eventHub *streaming.Hub
+
+// ssnCipher is the field-level cipher CreateLoanApplication/
+// GetLoanApplicationByID use to encrypt/decrypt ApplicantSSN; see
+// newSSNCipher for how the KeyManager is picked.
+var ssnCipher crypto.FieldCipher
+
+// dbPool is set by newRepository when DATABASE_URL is configured, so
+// newAuditLogger can reuse the same connection pool instead of opening a
+// second one.
+var dbPool *pgxpool.Pool
+
+// auditLogger records state-changing operations and OAuth2 logins to the
+// hash-chained audit trail; see newAuditLogger for how the Store is picked.
+var auditLogger *audit.Logger
 
-var documentQueue = make(chan DocumentJob, 100) // worker queue
-var processingStatus = make(map[int]string)     // track progress status
+const defaultExtractionWorkers = 4
+const localKEKFile = "local_kek.keys"
 
 // RUNNING API: go run main.go
 func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Init(ctx)
+	if err != nil {
+		slog.Error("tracing: init failed", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			slog.Error("tracing: shutdown failed", "error", err)
+		}
+	}()
+
+	oauth2server.Init()
+	repo = newRepository()
+	ssnCipher = newSSNCipher()
+	auditLogger = newAuditLogger()
+	oauth2server.SetAuditRecorder(oauthAuditAdapter{logger: auditLogger})
+
+	eventHub = streaming.NewHub()
+	extractionPool = extraction.NewPool(extractionWorkerCount(), repo, extraction.NewPDFExtractor(), extraction.WithPublisher(eventHub), extraction.WithSSNEncryptor(ssnCipher))
+	extractionPool.Start(ctx)
+
 	r := gin.New()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(ErrorHandlingMiddleware())
 	r.Use(RequestLogger())
+	r.Use(MetricsMiddleware())
 
-	r.POST("/login", LoginHandler)
 	r.GET("/test", TestAPI)
+	r.GET("/metrics", metrics.Handler())
+
+	r.GET("/oauth/authorize", oauth2server.AuthorizeHandler)
+	r.POST("/oauth/authorize", oauth2server.AuthorizeHandler)
+	r.POST("/oauth/token", oauth2server.TokenHandler)
+	r.POST("/oauth/revoke", oauth2server.RevokeHandler)
+	r.POST("/oauth/introspect", oauth2server.IntrospectHandler)
+	r.GET("/.well-known/jwks.json", oauth2server.JWKSHandler)
+	r.GET("/.well-known/openid-configuration", oauth2server.OIDCDiscoveryHandler)
 
 	auth := r.Group("/")
-	auth.Use(JWTMiddleware())
-	auth.POST("/loan-applications", RoleRequired("loan_officer"), CreateLoanApplication)
-	auth.GET("/loan-applications", RoleRequired("loan_officer", "underwriter"), GetLoanApplications)
-	auth.GET("/loan-applications/:id", RoleRequired("loan_officer", "underwriter", "applicant"), GetLoanApplicationByID)
-	auth.PUT("/loan-applications/:id/status", RoleRequired("underwriter"), UpdateStatus)
-	auth.POST("/loan-applications/:id/documents", RoleRequired("loan_officer"), UploadDocuments)
+	auth.Use(oauth2server.BearerAuth())
+	auth.POST("/loan-applications", oauth2server.ScopeRequired(oauth2server.ScopeLoanWrite), CreateLoanApplication)
+	auth.GET("/loan-applications", oauth2server.ScopeRequired(oauth2server.ScopeLoanRead), GetLoanApplications)
+	auth.GET("/loan-applications/:id", oauth2server.ScopeRequired(oauth2server.ScopeLoanRead), GetLoanApplicationByID)
+	auth.PUT("/loan-applications/:id/status", oauth2server.ScopeRequired(oauth2server.ScopeLoanApprove), UpdateStatus)
+	auth.POST("/loan-applications/:id/documents", oauth2server.ScopeRequired(oauth2server.ScopeDocUpload), UploadDocuments)
+	auth.GET("/loan-applications/:id/extraction", oauth2server.ScopeRequired(oauth2server.ScopeLoanRead), GetExtraction)
+	auth.GET("/loan-applications/:id/status", oauth2server.ScopeRequired(oauth2server.ScopeLoanRead), GetProcessingStatus)
+	auth.GET("/audit", oauth2server.ScopeRequired(oauth2server.ScopeAuditRead), GetAuditLog)
+
+	// SSE/WS use BearerAuthQuery instead of BearerAuth: EventSource (and
+	// most browser websocket clients) can't set an Authorization header,
+	// so these two routes also accept the token as ?access_token=.
+	stream := r.Group("/")
+	stream.Use(oauth2server.BearerAuthQuery())
+	stream.GET("/loan-applications/:id/events", oauth2server.ScopeRequired(oauth2server.ScopeLoanRead), streaming.SSEHandler(eventHub))
+	stream.GET("/ws", oauth2server.ScopeRequired(oauth2server.ScopeLoanRead), streaming.WSHandler(eventHub))
 
 	go func() {
-		for job := range documentQueue {
-			processDocument(job)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := extractionPool.Shutdown(shutdownCtx); err != nil {
+			slog.Error("extraction pool did not drain cleanly", "error", err)
 		}
 	}()
 
 	r.Run(":8080")
 }
 
-func processDocument(job DocumentJob) {
-	log.Printf("[PROCESSING] AppID=%d, File=%s", job.AppID, job.FilePath)
-	processingStatus[job.AppID] = "processing"
+// extractionWorkerCount lets ops scale the pool via EXTRACTION_WORKERS
+// without a redeploy; defaults to defaultExtractionWorkers.
+func extractionWorkerCount() int {
+	n, err := strconv.Atoi(os.Getenv("EXTRACTION_WORKERS"))
+	if err != nil || n < 1 {
+		return defaultExtractionWorkers
+	}
+	return n
+}
+
+// newRepository picks the Repository implementation for this process: an
+// in-memory one unless DATABASE_URL is set, in which case it connects to
+// Postgres and runs any pending migrations before returning.
+func newRepository() storage.Repository {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		return storage.NewMemoryRepository()
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		slog.Error("storage: connect to postgres", "error", err)
+		os.Exit(1)
+	}
+	if err := storage.Migrate(ctx, pool); err != nil {
+		slog.Error("storage: run migrations", "error", err)
+		os.Exit(1)
+	}
+	dbPool = pool
+	return storage.NewPostgresRepository(pool)
+}
+
+// newAuditLogger picks the Store backing the audit trail: Postgres when
+// newRepository already opened a pool (so the hash chain survives
+// restarts and is consistent with the rest of the data), otherwise an
+// in-memory store so the app still runs with nothing configured.
+func newAuditLogger() *audit.Logger {
+	if dbPool != nil {
+		return audit.NewLogger(audit.NewPostgresStore(dbPool))
+	}
+	return audit.NewLogger(audit.NewMemoryStore())
+}
+
+// oauthAuditAdapter satisfies oauth2server.AuditRecorder by translating a
+// login outcome into an audit.Event, keeping audit's Event shape out of
+// oauth2server entirely.
+type oauthAuditAdapter struct {
+	logger *audit.Logger
+}
 
-	// Dummy proses: di sini nanti tinggal panggil pdf extractor
-	time.Sleep(3 * time.Second) // simulasi proses PDF
+func (a oauthAuditAdapter) RecordLogin(ctx context.Context, clientID, outcome, requestID, ip, userAgent string) {
+	a.logger.Record(ctx, audit.Event{
+		Actor:      clientID,
+		Action:     "login",
+		Resource:   "oauth_client",
+		ResourceID: clientID,
+		Outcome:    outcome,
+		RequestID:  requestID,
+		IP:         ip,
+		UserAgent:  userAgent,
+	})
+}
 
-	// Update hasil ke status map
-	log.Printf("[DONE] AppID=%d processed", job.AppID)
-	processingStatus[job.AppID] = "completed"
+// auditActor identifies who performed a request for audit purposes: the
+// subject claim if the token carries one (authorization_code grants), else
+// the client_id (client_credentials grants have no subject).
+func auditActor(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return userID
+	}
+	return c.GetString("client_id")
+}
+
+// mustJSON marshals v for an audit Event's Before/After fields; v is always
+// a small literal built at the call site, so a marshal error here would be
+// a programmer error, not an operational one.
+func mustJSON(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("audit: failed to marshal audit payload", "error", err)
+		return nil
+	}
+	return raw
+}
+
+// newSSNCipher picks the KeyManager backing ApplicantSSN's envelope
+// encryption: AWS KMS or Vault Transit in production (selected by which
+// config env var is set), falling back to a local file-based KEK for dev
+// so the app still runs with nothing configured.
+func newSSNCipher() crypto.FieldCipher {
+	var keys crypto.KeyManager
+	switch {
+	case os.Getenv("AWS_KMS_KEY_ID") != "":
+		client := kms.NewFromConfig(awsConfig())
+		keys = crypto.NewKMSKeyManager(client, os.Getenv("AWS_KMS_KEY_ID"))
+	case os.Getenv("VAULT_TRANSIT_KEY") != "":
+		client, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+		if err != nil {
+			slog.Error("crypto: init vault client", "error", err)
+			os.Exit(1)
+		}
+		keys = crypto.NewVaultKeyManager(client, os.Getenv("VAULT_TRANSIT_KEY"))
+	default:
+		local, err := crypto.NewLocalFileKeyManager(localKEKFile)
+		if err != nil {
+			slog.Error("crypto: init local kek", "error", err)
+			os.Exit(1)
+		}
+		keys = local
+	}
+	return crypto.NewEnvelopeCipher(keys)
+}
+
+func awsConfig() aws.Config {
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		slog.Error("crypto: load aws config", "error", err)
+		os.Exit(1)
+	}
+	return cfg
 }
 
 // testing API
@@ -94,128 +287,175 @@ func maskSSN(ssn string) string {
 	return "***-**-" + ssn[len(ssn)-4:]
 }
 
+// respondError is the single helper every handler and middleware uses to
+// write an error response; it just forwards to apierror so the response
+// shape (and problem+json negotiation) stays in one place.
+func respondError(c *gin.Context, err error) {
+	apierror.RespondError(c, err)
+}
+
+// newRequestID returns a short random hex id; good enough to correlate log
+// lines and error payloads, no need for a full UUID implementation here.
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
 func ErrorHandlingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if rec := recover(); rec != nil {
 				appID := c.Param("id")
-				log.Printf("[PANIC] AppID=%s: %v\n%s", appID, rec, debug.Stack())
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error":          "Internal server error",
-					"application_id": appID,
-				})
+				slog.ErrorContext(c.Request.Context(), "panic recovered", append([]any{"application_id", appID, "panic", rec, "stack", string(debug.Stack())}, traceAttrs(c.Request.Context())...)...)
+				respondError(c, apierror.New(apierror.ErrInternal).WithApplicationID(appID))
 			}
 		}()
 		c.Next()
 	}
 }
 
+// RequestLogger assigns (or propagates) the request id, starts the root
+// span for the request, and logs the request with both IDs attached so log
+// lines and traces can be correlated.
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log.Printf("[REQUEST] %s %s", c.Request.Method, c.Request.URL.Path)
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header("X-Request-ID", requestID)
+
+		ctx, span := tracing.Tracer().Start(c.Request.Context(), c.Request.Method+" "+c.FullPath())
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		slog.InfoContext(ctx, "request", append([]any{"request_id", requestID, "method", c.Request.Method, "path", c.Request.URL.Path}, traceAttrs(ctx)...)...)
 		c.Next()
 	}
 }
 
-func JWTMiddleware() gin.HandlerFunc {
+// MetricsMiddleware records http_request_duration_seconds for every
+// request, labeled by the route pattern (not the raw path, so path
+// parameters don't explode the series cardinality).
+func MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing token"})
-			return
-		}
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return jwtSecret, nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			return
-		}
-		claims := token.Claims.(jwt.MapClaims)
-		c.Set("user_id", claims["user_id"])
-		c.Set("role", claims["role"])
+		start := time.Now()
 		c.Next()
-	}
-}
 
-func RoleRequired(allowedRoles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := c.GetString("role")
-		for _, allowed := range allowedRoles {
-			if role == allowed {
-				c.Next()
-				return
-			}
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
 		}
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(time.Since(start).Seconds())
 	}
 }
 
-func LoginHandler(c *gin.Context) {
-	var logInput struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+// traceAttrs returns the current span's trace/span IDs as slog attribute
+// pairs, or nil if ctx carries no valid span (e.g. background jobs run
+// without OTEL_EXPORTER_OTLP_ENDPOINT configured still get a span, but a
+// bare context.Background() call site wouldn't).
+func traceAttrs(ctx context.Context) []any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
 	}
-	if err := c.ShouldBindJSON(&logInput); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-		return
-	}
-
-	// hardcoded akun
-	// jenis account ada 3
-	accounts := map[string]struct {
-		Password string
-		Role     string
-	}{
-		"officer":     {"123456", "loan_officer"},
-		"underwriter": {"123456", "underwriter"},
-		"applicant":   {"123456", "applicant"},
-	}
-	account, ok := accounts[logInput.Username]
-	if !ok || logInput.Password != account.Password {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
-		return
-	}
-	claims := jwt.MapClaims{
-		"user_id": logInput.Username,
-		"role":    account.Role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, _ := token.SignedString(jwtSecret)
-	c.JSON(http.StatusOK, gin.H{"token": tokenStr})
+	return []any{"trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()}
 }
 
 func CreateLoanApplication(c *gin.Context) {
 	var input LoanApplication
 	if err := c.ShouldBindJSON(&input); err != nil || input.ApplicantName == "" || input.ApplicantSSN == "" || input.LoanAmount <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		respondError(c, apierror.New(apierror.ErrInvalidInput))
 		return
 	}
-	input.ID = currentID
 	input.Status = "pending"
 	input.MaskedSSN = maskSSN(input.ApplicantSSN)
 	input.SubmittedAt = time.Now()
-	loanApplications[currentID] = input
-	currentID++
-	c.JSON(http.StatusCreated, input)
+
+	// The encryption AAD binds the ciphertext to the application's ID, which
+	// doesn't exist until after the insert - so the record is created with
+	// the SSN field blank and then sealed in place. The repository (and
+	// anything that persists alongside it, like Postgres) never sees
+	// plaintext; MaskedSSN above is what callers without loan:read-pii get
+	// back.
+	plaintextSSN := input.ApplicantSSN
+	input.ApplicantSSN = ""
+
+	created, err := repo.CreateApplication(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+		return
+	}
+
+	ciphertext, keyID, err := ssnCipher.Encrypt(c.Request.Context(), plaintextSSN, crypto.SSNAAD(created.ID))
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage("Failed to encrypt SSN"))
+		return
+	}
+	if err := repo.RewrapSSN(c.Request.Context(), created.ID, ciphertext, keyID); err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage("Failed to persist encrypted SSN"))
+		return
+	}
+
+	created.ApplicantSSN = ""
+	created.SSNKeyID = keyID
+
+	auditLogger.Record(c.Request.Context(), audit.Event{
+		Actor:      auditActor(c),
+		Role:       c.GetString("role"),
+		Action:     "create_loan_application",
+		Resource:   "loan_application",
+		ResourceID: strconv.Itoa(created.ID),
+		After:      mustJSON(gin.H{"status": created.Status, "applicant_name": created.ApplicantName}),
+		RequestID:  c.GetString("request_id"),
+		Outcome:    "success",
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+
+	c.JSON(http.StatusCreated, created)
 }
 
 func GetLoanApplicationByID(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	app, exists := loanApplications[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+	app, err := repo.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithField("id", id))
 		return
 	}
 	role := c.GetString("role")
 	userID := c.GetString("user_id")
 	if role == "applicant" && userID != app.ApplicantName {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this application"})
+		respondError(c, apierror.New(apierror.ErrForbidden).WithMessage("Not authorized to view this application"))
 		return
 	}
-	app.MaskedSSN = maskSSN(app.ApplicantSSN)
+
+	if oauth2server.HasScope(c, oauth2server.ScopeLoanReadPII) {
+		plaintext, err := ssnCipher.Decrypt(c.Request.Context(), app.ApplicantSSN, app.SSNKeyID, crypto.SSNAAD(app.ID))
+		if err != nil {
+			respondError(c, apierror.New(apierror.ErrInternal).WithMessage("Failed to decrypt SSN"))
+			return
+		}
+		app.ApplicantSSN = plaintext
+
+		auditLogger.Record(c.Request.Context(), audit.Event{
+			Actor:      auditActor(c),
+			Role:       role,
+			Action:     "read_pii",
+			Resource:   "loan_application",
+			ResourceID: strconv.Itoa(app.ID),
+			RequestID:  c.GetString("request_id"),
+			Outcome:    "success",
+			IP:         c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+		})
+	} else {
+		app.ApplicantSSN = ""
+	}
 	c.JSON(http.StatusOK, app)
 }
 
@@ -226,51 +466,63 @@ func GetLoanApplications(c *gin.Context) {
 	name := strings.ToLower(c.Query("name"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	var filtered []LoanApplication
-	for _, app := range loanApplications {
-		if status != "" && strings.ToLower(app.Status) != status {
-			continue
-		}
-		if name != "" && !strings.Contains(strings.ToLower(app.ApplicantName), name) {
-			continue
-		}
-		app.MaskedSSN = maskSSN(app.ApplicantSSN)
-		filtered = append(filtered, app)
-	}
-	end := offset + limit
-	if end > len(filtered) {
-		end = len(filtered)
+
+	result, err := repo.ListApplications(c.Request.Context(), storage.Filter{Status: status, Name: name}, storage.Page{Limit: limit, Offset: offset})
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+		return
 	}
-	if offset > len(filtered) {
-		offset = len(filtered)
+	for i := range result.Results {
+		// MaskedSSN was computed from the plaintext at create time and
+		// persisted alongside the ciphertext; ApplicantSSN itself is never
+		// included in a list response, PII scope or not.
+		result.Results[i].ApplicantSSN = ""
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"total":   len(filtered),
+		"total":   result.Total,
 		"limit":   limit,
 		"offset":  offset,
-		"results": filtered[offset:end],
+		"results": result.Results,
 	})
 }
 
 // ini untuk update status
 func UpdateStatus(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	app, exists := loanApplications[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
-		return
-	}
 	var body struct {
 		Status string `json:"status"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil || body.Status == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
+		respondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("Status is required"))
+		return
+	}
+	before, err := repo.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithField("id", id))
+		return
+	}
+
+	app, err := repo.UpdateStatus(c.Request.Context(), id, body.Status)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithField("id", id))
 		return
 	}
-	app.Status = body.Status
-	now := time.Now()
-	app.ProcessedAt = &now
-	loanApplications[id] = app
+	metrics.LoanStateTransitions.WithLabelValues(app.Status).Inc()
+
+	auditLogger.Record(c.Request.Context(), audit.Event{
+		Actor:      auditActor(c),
+		Role:       c.GetString("role"),
+		Action:     "update_status",
+		Resource:   "loan_application",
+		ResourceID: strconv.Itoa(id),
+		Before:     mustJSON(gin.H{"status": before.Status}),
+		After:      mustJSON(gin.H{"status": app.Status}),
+		RequestID:  c.GetString("request_id"),
+		Outcome:    "success",
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+
 	c.JSON(http.StatusOK, app)
 }
 
@@ -294,11 +546,16 @@ func UpdateStatus(c *gin.Context) {
 // 	c.JSON(http.StatusOK, app)
 // }
 
+// GetProcessingStatus serves GET /loan-applications/:id/status, a
+// poll-based fallback for clients that can't hold open the SSE/WS streams
+// in streaming.Hub - same ScopeLoanRead gate as those, no extra ownership
+// check, since all it reveals is the processing status already visible
+// through the event stream.
 func GetProcessingStatus(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	status, exists := processingStatus[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No processing job for this application"})
+	status, err := repo.GetProcessingStatus(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithMessage("No processing job for this application").WithField("id", id))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"application_id": id, "status": status})
@@ -306,40 +563,195 @@ func GetProcessingStatus(c *gin.Context) {
 
 func UploadDocuments(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	app, exists := loanApplications[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+	if _, err := repo.GetApplication(c.Request.Context(), id); err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithField("id", id))
 		return
 	}
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		respondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("No file uploaded"))
 		return
 	}
 
 	if !strings.HasSuffix(file.Filename, ".pdf") {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File must be a PDF"})
+		respondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("File must be a PDF"))
 		return
 	}
 
 	savePath := fmt.Sprintf("uploads/%d_%s", id, file.Filename)
 	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage("Failed to save file"))
 		return
 	}
 
-	app.DocumentsUploaded = append(app.DocumentsUploaded, file.Filename)
-	loanApplications[id] = app
-
-	processingStatus[id] = "queued"
+	if _, err := repo.AppendDocument(c.Request.Context(), id, file.Filename); err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+		return
+	}
+	if err := repo.SetProcessingStatus(c.Request.Context(), id, "queued"); err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+		return
+	}
 
-	documentQueue <- DocumentJob{
-		AppID:    id,
-		FilePath: savePath,
+	job, err := repo.CreateDocumentJob(c.Request.Context(), id, savePath)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+		return
 	}
 
+	// Carry the request's trace context onto the job so the worker's span
+	// is a child of this request's, even though processing happens on a
+	// different goroutine (and possibly well after this handler returns).
+	carrier := propagation.MapCarrier{}
+	tracing.Propagator().Inject(c.Request.Context(), carrier)
+	extractionPool.Submit(extraction.Job{JobID: job.ID, ApplicationID: id, FilePath: savePath, TraceParent: carrier.Get("traceparent")})
+
+	auditLogger.Record(c.Request.Context(), audit.Event{
+		Actor:      auditActor(c),
+		Role:       c.GetString("role"),
+		Action:     "upload_documents",
+		Resource:   "loan_application",
+		ResourceID: strconv.Itoa(id),
+		After:      mustJSON(gin.H{"filename": file.Filename}),
+		RequestID:  c.GetString("request_id"),
+		Outcome:    "success",
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "File uploaded and queued for processing",
 	})
 }
+
+// GetExtraction returns the parsed fields from the most recent extraction
+// job for this application, so underwriters can review what the pipeline
+// found without opening the PDF themselves. It enforces the same
+// ownership check as GetLoanApplicationByID, and the SSN the extractor
+// found is only decrypted and included for callers with loan:read-pii -
+// everyone else sees the rest of the fields without it.
+func GetExtraction(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	app, err := repo.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithField("id", id))
+		return
+	}
+	role := c.GetString("role")
+	userID := c.GetString("user_id")
+	if role == "applicant" && userID != app.ApplicantName {
+		respondError(c, apierror.New(apierror.ErrForbidden).WithMessage("Not authorized to view this application"))
+		return
+	}
+
+	job, err := repo.GetLatestDocumentJobForApplication(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrNotFound).WithMessage("No extraction job for this application").WithField("id", id))
+		return
+	}
+
+	var stored extraction.StoredFields
+	if job.ExtractedFields != "" {
+		if err := json.Unmarshal([]byte(job.ExtractedFields), &stored); err != nil {
+			respondError(c, apierror.New(apierror.ErrInternal).WithMessage("Failed to parse extracted fields"))
+			return
+		}
+	}
+
+	fields := gin.H{"page_count": stored.PageCount, "income": stored.Income, "employer": stored.Employer}
+	if stored.SSNEncrypted != "" && oauth2server.HasScope(c, oauth2server.ScopeLoanReadPII) {
+		plaintext, err := ssnCipher.Decrypt(c.Request.Context(), stored.SSNEncrypted, stored.SSNKeyID, crypto.SSNAAD(id))
+		if err != nil {
+			respondError(c, apierror.New(apierror.ErrInternal).WithMessage("Failed to decrypt SSN"))
+			return
+		}
+		fields["ssn"] = plaintext
+
+		auditLogger.Record(c.Request.Context(), audit.Event{
+			Actor:      auditActor(c),
+			Role:       role,
+			Action:     "read_pii",
+			Resource:   "loan_application",
+			ResourceID: strconv.Itoa(id),
+			RequestID:  c.GetString("request_id"),
+			Outcome:    "success",
+			IP:         c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"application_id":   job.ApplicationID,
+		"status":           job.Status,
+		"attempts":         job.Attempts,
+		"last_error":       job.LastError,
+		"extracted_fields": fields,
+	})
+}
+
+// GetAuditLog serves GET /audit?resource=&id=&actor=&action=&since=&until=
+// restricted to audit:read. Default response is JSON; ?format=csv or
+// ?format=jsonl switch to those for offline review/ingestion into a SIEM.
+func GetAuditLog(c *gin.Context) {
+	filter := audit.Filter{
+		Resource:   c.Query("resource"),
+		ResourceID: c.Query("id"),
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+	}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			respondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("since must be RFC3339"))
+			return
+		}
+		filter.Since = t
+	}
+	if until := c.Query("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			respondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("until must be RFC3339"))
+			return
+		}
+		filter.Until = t
+	}
+
+	events, err := auditLogger.Query(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+		return
+	}
+
+	switch c.Query("format") {
+	case "csv":
+		writeAuditCSV(c, events)
+	case "jsonl":
+		writeAuditJSONL(c, events)
+	default:
+		c.JSON(http.StatusOK, gin.H{"results": events})
+	}
+}
+
+func writeAuditCSV(c *gin.Context, events []audit.Event) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="audit.csv"`)
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "time", "actor", "role", "action", "resource", "resource_id", "outcome", "request_id", "ip", "user_agent", "prev_hash", "hash"})
+	for _, evt := range events {
+		w.Write([]string{
+			strconv.FormatInt(evt.ID, 10), evt.Time.Format(time.RFC3339Nano), evt.Actor, evt.Role,
+			evt.Action, evt.Resource, evt.ResourceID, evt.Outcome, evt.RequestID, evt.IP, evt.UserAgent, evt.PrevHash, evt.Hash,
+		})
+	}
+	w.Flush()
+}
+
+func writeAuditJSONL(c *gin.Context, events []audit.Event) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="audit.jsonl"`)
+	enc := json.NewEncoder(c.Writer)
+	for _, evt := range events {
+		enc.Encode(evt)
+	}
+}
@@ -3,50 +3,141 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+
+	"loanapi/audit"
+	"loanapi/crypto"
+	"loanapi/oauth2server"
+	"loanapi/storage"
 )
 
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
+	oauth2server.Init()
+	repo = storage.NewMemoryRepository()
+
+	keys, err := crypto.NewLocalFileKeyManager(filepath.Join(os.TempDir(), fmt.Sprintf("loanapi-test-kek-%d", time.Now().UnixNano())))
+	if err != nil {
+		panic(err)
+	}
+	ssnCipher = crypto.NewEnvelopeCipher(keys)
+
+	auditLogger = audit.NewLogger(audit.NewMemoryStore())
+	oauth2server.SetAuditRecorder(oauthAuditAdapter{logger: auditLogger})
+
 	r := gin.New()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(ErrorHandlingMiddleware())
 	r.Use(RequestLogger())
 
-	r.POST("/login", LoginHandler)
+	r.POST("/oauth/token", oauth2server.TokenHandler)
+	r.POST("/oauth/revoke", oauth2server.RevokeHandler)
 	auth := r.Group("/")
-	auth.Use(JWTMiddleware())
+	auth.Use(oauth2server.BearerAuth())
 	auth.POST("/loan-applications", CreateLoanApplication)
 
 	return r
 }
 
-func TestLoginSuccess(t *testing.T) {
+// requestToken performs a client_credentials token request and returns the
+// raw response so callers can assert on status code as well as body.
+func requestToken(router *gin.Engine, clientID, clientSecret, scope string) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+	form.Set("scope", scope)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOAuthTokenClientCredentials(t *testing.T) {
 	router := setupRouter()
 
-	payload := map[string]string{
-		"username": "officer",
-		"password": "123456",
-	}
-	body, _ := json.Marshal(payload)
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", "loan-officer-web")
+	form.Set("client_secret", "loan-officer-web-secret")
+	form.Set("scope", "loan:write")
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, 200, w.Code)
-	var resp map[string]string
+	var resp map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Nil(t, err)
-	assert.NotEmpty(t, resp["token"])
+	assert.NotEmpty(t, resp["access_token"])
+}
+
+func TestOAuthTokenDeniesScopeOutsideClientRegistration(t *testing.T) {
+	router := setupRouter()
+
+	// loan-officer-web is only registered for loan:read, loan:write,
+	// doc:upload - loan:approve is underwriter-only.
+	w := requestToken(router, "loan-officer-web", "loan-officer-web-secret", "loan:approve")
+
+	assert.NotEqual(t, 200, w.Code)
+	var resp map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Empty(t, resp["access_token"])
+}
+
+func TestOAuthRevokedTokenIsRejected(t *testing.T) {
+	router := setupRouter()
+
+	w := requestToken(router, "loan-officer-web", "loan-officer-web-secret", "loan:write")
+	assert.Equal(t, 200, w.Code)
+	var tokenResp map[string]interface{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &tokenResp))
+	accessToken, _ := tokenResp["access_token"].(string)
+	assert.NotEmpty(t, accessToken)
+
+	revokeForm := url.Values{}
+	revokeForm.Set("token", accessToken)
+	revokeW := httptest.NewRecorder()
+	revokeReq, _ := http.NewRequest("POST", "/oauth/revoke", strings.NewReader(revokeForm.Encode()))
+	revokeReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(revokeW, revokeReq)
+	assert.Equal(t, 200, revokeW.Code)
+
+	payload := map[string]interface{}{
+		"applicant_name": "Jane Smith",
+		"applicant_ssn":  "987-65-4321",
+		"loan_amount":    20000,
+		"loan_purpose":   "Kendaraan",
+		"annual_income":  90000,
+		"credit_score":   700,
+	}
+	body, _ := json.Marshal(payload)
+
+	useW := httptest.NewRecorder()
+	useReq, _ := http.NewRequest("POST", "/loan-applications", bytes.NewBuffer(body))
+	useReq.Header.Set("Content-Type", "application/json")
+	useReq.Header.Set("Authorization", "Bearer "+accessToken)
+	router.ServeHTTP(useW, useReq)
+
+	assert.Equal(t, http.StatusUnauthorized, useW.Code)
 }
 
 func TestCreateLoanApplicationUnauthorized(t *testing.T) {
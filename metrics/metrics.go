@@ -0,0 +1,63 @@
+// Package metrics holds the process-wide Prometheus collectors. It's a leaf
+// package - no business package needs to be reachable from here - so
+// everything else just imports it directly and calls the package-level
+// vars, the same way packages reach for apierror.
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// HTTPRequestDuration tracks every handled request by route (not raw
+	// path, so /loan-applications/:id doesn't explode into one series per
+	// application), method and status code.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// JWTAuthFailures counts bearer tokens rejected by oauth2server.BearerAuth
+	// (missing, malformed, or failing signature/kid verification).
+	JWTAuthFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "jwt_auth_failures_total",
+		Help: "Total number of bearer tokens rejected during authentication.",
+	})
+
+	// DocumentQueueDepth is the number of extraction jobs currently queued
+	// in the extraction.Pool and not yet picked up by a worker, so ops can
+	// see backpressure building before the dead letter queue starts filling
+	// up. A job stops counting as soon as a worker dequeues it.
+	DocumentQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "document_queue_depth",
+		Help: "Number of document extraction jobs queued and not yet picked up by a worker.",
+	})
+
+	// ProcessDocumentDuration times extraction.Pool.process, broken down by
+	// how the attempt ended.
+	ProcessDocumentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "process_document_duration_seconds",
+		Help:    "Duration of a single document extraction attempt in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"outcome"})
+
+	// LoanStateTransitions counts loan application status changes made
+	// through UpdateStatus, labeled by the new status. The extraction
+	// pipeline's processing-status changes are not counted here.
+	LoanStateTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "loan_state_transitions_total",
+		Help: "Total number of loan application state transitions.",
+	}, []string{"status"})
+)
+
+func init() {
+	prometheus.MustRegister(HTTPRequestDuration, JWTAuthFailures, DocumentQueueDepth, ProcessDocumentDuration, LoanStateTransitions)
+}
+
+// Handler serves /metrics in the Prometheus text exposition format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
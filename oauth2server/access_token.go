@@ -0,0 +1,117 @@
+package oauth2server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtAccessGenerate implements oauth2.AccessGenerate, signing access tokens
+// as RS256 JWTs with whatever key is currently active in the rotating key
+// set (see keys.go). Refresh tokens are opaque random strings looked up in
+// the TokenStore, same as before PKCE/JWT landed.
+type jwtAccessGenerate struct {
+	keys *keySet
+}
+
+// accessClaims is intentionally small: scope-based authorization only needs
+// the subject, client, scope and the legacy role (for the migration shim).
+type accessClaims struct {
+	jwt.RegisteredClaims
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+	Role     string `json:"role,omitempty"`
+}
+
+func (g *jwtAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic, isGenRefresh bool) (string, string, error) {
+	active := g.keys.Active()
+
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   data.UserID,
+			IssuedAt:  jwt.NewNumericDate(data.TokenInfo.GetAccessCreateAt()),
+			ExpiresAt: jwt.NewNumericDate(data.TokenInfo.GetAccessCreateAt().Add(data.TokenInfo.GetAccessExpiresIn())),
+		},
+		ClientID: data.Client.GetID(),
+		Scope:    data.TokenInfo.GetScope(),
+		Role:     legacyRoleForScope(data.TokenInfo.GetScope()),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = active.ID
+	access, err := token.SignedString(active.PrivateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	var refresh string
+	if isGenRefresh {
+		refresh, err = newOpaqueToken()
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return access, refresh, nil
+}
+
+func newOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// legacyRoleForScope is the reverse of the role->scope migration shim: it
+// picks the role with the largest scope set that the granted scopes fully
+// cover, so tokens minted by the new server still satisfy old role checks
+// during the rollout. It returns "" when no role's scopes are all granted,
+// so the role claim never expands a token beyond the scopes it was granted.
+func legacyRoleForScope(scope string) string {
+	granted := splitScope(scope)
+	best, bestScore := "", 0
+	for role, scopes := range roleScopes {
+		covered := true
+		for _, s := range scopes {
+			if !containsScope(granted, s) {
+				covered = false
+				break
+			}
+		}
+		if covered && len(scopes) > bestScore {
+			best, bestScore = role, len(scopes)
+		}
+	}
+	return best
+}
+
+func splitScope(scope string) []string {
+	var out []string
+	cur := ""
+	for _, r := range scope {
+		if r == ' ' {
+			if cur != "" {
+				out = append(out, cur)
+				cur = ""
+			}
+			continue
+		}
+		cur += string(r)
+	}
+	if cur != "" {
+		out = append(out, cur)
+	}
+	return out
+}
+
+func containsScope(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
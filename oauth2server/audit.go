@@ -0,0 +1,28 @@
+package oauth2server
+
+import "context"
+
+// AuditRecorder is the narrow interface oauth2server needs to log token
+// issuance events. It's satisfied by *audit.Logger without oauth2server
+// importing the audit package directly - the same pattern extraction.Pool
+// uses for its Publisher, to keep this package free of a dependency on
+// something that sits above it in the stack.
+type AuditRecorder interface {
+	RecordLogin(ctx context.Context, clientID, outcome, requestID, ip, userAgent string)
+}
+
+var auditRecorder AuditRecorder
+
+// SetAuditRecorder wires r in to receive a RecordLogin call for every
+// /oauth/token request. Called once from main() during startup; if it's
+// never called, recordLogin is a no-op.
+func SetAuditRecorder(r AuditRecorder) {
+	auditRecorder = r
+}
+
+func recordLogin(ctx context.Context, clientID, outcome, requestID, ip, userAgent string) {
+	if auditRecorder == nil {
+		return
+	}
+	auditRecorder.RecordLogin(ctx, clientID, outcome, requestID, ip, userAgent)
+}
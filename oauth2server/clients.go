@@ -0,0 +1,76 @@
+package oauth2server
+
+// Client is a registered OAuth2 client. The loan officer / underwriter web
+// app is confidential (has a secret); the mobile applicant app is public and
+// relies on PKCE instead.
+type Client struct {
+	ID          string
+	Secret      string
+	Domain      string
+	Public      bool
+	Scopes      []string
+	RedirectURI string
+}
+
+// Known scopes. Each legacy role string maps onto a set of these (see
+// roleScopes below) so the migration shim in scopes.go can translate role
+// claims on the fly.
+const (
+	ScopeLoanRead    = "loan:read"
+	ScopeLoanWrite   = "loan:write"
+	ScopeLoanApprove = "loan:approve"
+	ScopeDocUpload   = "doc:upload"
+	// ScopeLoanReadPII additionally authorizes decrypting PII fields (the
+	// applicant SSN) that loan:read alone only sees masked.
+	ScopeLoanReadPII = "loan:read-pii"
+	// ScopeAuditRead authorizes reading the audit trail via GET /audit; it's
+	// deliberately separate from every loan:* scope since auditors review
+	// actions, they don't take them.
+	ScopeAuditRead = "audit:read"
+)
+
+// registeredClients seeds the ClientStore on boot. In production this would
+// live in the repository (see storage.Repository) instead of a literal; for
+// now it mirrors the hardcoded accounts map LoginHandler used to have.
+var registeredClients = []Client{
+	{
+		ID:     "loan-officer-web",
+		Secret: "loan-officer-web-secret",
+		Domain: "http://localhost:8080",
+		Public: false,
+		Scopes: []string{ScopeLoanRead, ScopeLoanWrite, ScopeDocUpload},
+	},
+	{
+		ID:     "underwriter-web",
+		Secret: "underwriter-web-secret",
+		Domain: "http://localhost:8080",
+		Public: false,
+		Scopes: []string{ScopeLoanRead, ScopeLoanApprove, ScopeLoanReadPII},
+	},
+	{
+		ID:          "applicant-mobile",
+		Domain:      "app://applicant-mobile/callback",
+		Public:      true,
+		RedirectURI: "app://applicant-mobile/callback",
+		Scopes:      []string{ScopeLoanRead},
+	},
+	{
+		ID:     "auditor-web",
+		Secret: "auditor-web-secret",
+		Domain: "http://localhost:8080",
+		Public: false,
+		Scopes: []string{ScopeAuditRead},
+	},
+}
+
+// roleScopes is the backward-compatibility shim: access tokens minted by
+// this server carry a legacy "role" claim next to "scope" (see
+// legacyRoleForScope), and a role is mapped onto the new scopes here. HS256
+// tokens from the old hardcoded LoginHandler are not accepted at all:
+// authenticate only verifies RS256 tokens signed by the current key set.
+var roleScopes = map[string][]string{
+	"loan_officer": {ScopeLoanRead, ScopeLoanWrite, ScopeDocUpload},
+	"underwriter":  {ScopeLoanRead, ScopeLoanApprove, ScopeLoanReadPII},
+	"applicant":    {ScopeLoanRead},
+	"auditor":      {ScopeAuditRead},
+}
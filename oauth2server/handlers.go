@@ -0,0 +1,132 @@
+package oauth2server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"loanapi/apierror"
+)
+
+// AuthorizeHandler serves GET/POST /oauth/authorize, the front channel of
+// the authorization-code grant (with PKCE for public clients).
+func AuthorizeHandler(c *gin.Context) {
+	if err := authServer.HandleAuthorizeRequest(c.Writer, c.Request); err != nil {
+		apierror.RespondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage(err.Error()))
+	}
+}
+
+// TokenHandler serves POST /oauth/token for the authorization_code,
+// refresh_token, and client_credentials grants.
+func TokenHandler(c *gin.Context) {
+	clientID := c.PostForm("client_id")
+	requestID := c.GetString("request_id")
+
+	outcome := "success"
+	if err := authServer.HandleTokenRequest(c.Writer, c.Request); err != nil {
+		outcome = "failure"
+		apierror.RespondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage(err.Error()))
+	}
+	recordLogin(c.Request.Context(), clientID, outcome, requestID, c.ClientIP(), c.Request.UserAgent())
+}
+
+// RevokeHandler serves POST /oauth/revoke (RFC 7009): the caller presents a
+// token, and both halves of the pair it belongs to (access and refresh) are
+// removed from the TokenStore. Unlike RFC 7009's "always 200" guidance, we
+// report failure when the token isn't found in the store at all, since a
+// caller that believes it revoked a token needs to know if that didn't
+// actually happen.
+func RevokeHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.PostForm("token")
+	if token == "" {
+		apierror.RespondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("token is required"))
+		return
+	}
+
+	if info, err := tokenStore.GetByAccess(ctx, token); err == nil && info != nil {
+		if err := tokenStore.RemoveByAccess(ctx, token); err != nil {
+			apierror.RespondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+			return
+		}
+		if refresh := info.GetRefresh(); refresh != "" {
+			if err := tokenStore.RemoveByRefresh(ctx, refresh); err != nil {
+				apierror.RespondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+				return
+			}
+		}
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if info, err := tokenStore.GetByRefresh(ctx, token); err == nil && info != nil {
+		if err := tokenStore.RemoveByRefresh(ctx, token); err != nil {
+			apierror.RespondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+			return
+		}
+		if access := info.GetAccess(); access != "" {
+			if err := tokenStore.RemoveByAccess(ctx, access); err != nil {
+				apierror.RespondError(c, apierror.New(apierror.ErrInternal).WithMessage(err.Error()))
+				return
+			}
+		}
+		c.Status(http.StatusOK)
+		return
+	}
+
+	apierror.RespondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("token not found"))
+}
+
+// IntrospectHandler serves POST /oauth/introspect (RFC 7662), used by
+// resource servers that would rather ask us than verify the JWT locally.
+func IntrospectHandler(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		apierror.RespondError(c, apierror.New(apierror.ErrInvalidInput).WithMessage("token is required"))
+		return
+	}
+	info, err := manager.LoadAccessToken(c.Request.Context(), token)
+	if err != nil || info == nil {
+		c.JSON(http.StatusOK, gin.H{"active": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"active":    true,
+		"client_id": info.GetClientID(),
+		"scope":     info.GetScope(),
+		"sub":       info.GetUserID(),
+		"exp":       info.GetAccessCreateAt().Add(info.GetAccessExpiresIn()).Unix(),
+	})
+}
+
+// JWKSHandler serves GET /.well-known/jwks.json so resource servers can
+// verify RS256 access tokens without calling back to /oauth/introspect.
+func JWKSHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"keys": keys.JWKS()})
+}
+
+// OIDCDiscoveryHandler serves GET /.well-known/openid-configuration.
+func OIDCDiscoveryHandler(c *gin.Context) {
+	issuer := issuerFromRequest(c)
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/oauth/authorize",
+		"token_endpoint":                        issuer + "/oauth/token",
+		"revocation_endpoint":                   issuer + "/oauth/revoke",
+		"introspection_endpoint":                issuer + "/oauth/introspect",
+		"jwks_uri":                              issuer + "/.well-known/jwks.json",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "client_credentials"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_post", "none"},
+		"scopes_supported":                      []string{ScopeLoanRead, ScopeLoanWrite, ScopeLoanApprove, ScopeDocUpload, ScopeLoanReadPII, ScopeAuditRead},
+	})
+}
+
+func issuerFromRequest(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,115 @@
+package oauth2server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"sync"
+)
+
+// signingKey is one entry in the rotating key set used to sign access
+// tokens. Old keys are kept around (but not used for new signatures) only
+// long enough for previously issued tokens to expire, then pruned.
+type signingKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+}
+
+// keySet is a small rotating RS256 key set backing both token signing and
+// the /.well-known/jwks.json endpoint.
+type keySet struct {
+	mu      sync.RWMutex
+	keys    []signingKey
+	current string
+}
+
+var keys = newKeySet()
+
+func newKeySet() *keySet {
+	ks := &keySet{}
+	if _, err := ks.rotate(); err != nil {
+		panic("oauth2server: failed to generate initial signing key: " + err.Error())
+	}
+	return ks
+}
+
+// rotate generates a new RSA key, makes it the active signing key, and keeps
+// the previous ones around so tokens signed with them still verify via JWKS.
+func (ks *keySet) rotate() (signingKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return signingKey{}, err
+	}
+	id := newKeyID()
+	sk := signingKey{ID: id, PrivateKey: priv}
+
+	ks.mu.Lock()
+	ks.keys = append(ks.keys, sk)
+	ks.current = id
+	ks.mu.Unlock()
+	return sk, nil
+}
+
+func newKeyID() string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Active returns the key currently used to sign new access tokens.
+func (ks *keySet) Active() signingKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for _, k := range ks.keys {
+		if k.ID == ks.current {
+			return k
+		}
+	}
+	return signingKey{}
+}
+
+// Find returns the key with the given ID, used when verifying a token's
+// "kid" header against a (possibly rotated-out) previous key.
+func (ks *keySet) Find(id string) (signingKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for _, k := range ks.keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return signingKey{}, false
+}
+
+// jwk is the JSON Web Key representation of an RSA public key, enough for
+// clients/introspectors to verify RS256 signatures.
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS renders the full key set (minus private material) for
+// /.well-known/jwks.json.
+func (ks *keySet) JWKS() []jwk {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	out := make([]jwk, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		pub := k.PrivateKey.PublicKey
+		out = append(out, jwk{
+			Kty: "RSA",
+			Use: "sig",
+			Kid: k.ID,
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return out
+}
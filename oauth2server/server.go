@@ -0,0 +1,116 @@
+// Package oauth2server wires up a real OAuth2 authorization server (built on
+// go-oauth2/oauth2/v4) to replace the old hardcoded LoginHandler. It issues
+// short-lived RS256 access tokens plus rotating refresh tokens, supports the
+// authorization-code grant with PKCE (S256) for public clients, and exposes
+// JWKS/OIDC discovery so resource servers and third parties can verify
+// tokens without sharing a secret.
+package oauth2server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	oautherrors "github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+// TokenStore is the persistence boundary for issued access/refresh tokens.
+// It's the library's interface re-exported under our own name so a future
+// Postgres-backed implementation (storage.Repository, chunk0-3) is a drop-in
+// replacement for the in-memory default below.
+type TokenStore = oauth2.TokenStore
+
+// ClientStore is the persistence boundary for registered OAuth2 clients.
+type ClientStore = oauth2.ClientStore
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	manager    *manage.Manager
+	authServer *server.Server
+	tokenStore oauth2.TokenStore
+)
+
+// Init builds the manager and authorization server. It must run once before
+// any of the HTTP handlers in handlers.go are registered.
+func Init() {
+	manager = manage.NewDefaultManager()
+	manager.SetAuthorizeCodeTokenCfg(&manage.Config{
+		AccessTokenExp:    accessTokenTTL,
+		RefreshTokenExp:   refreshTokenTTL,
+		IsGenerateRefresh: true,
+	})
+
+	tokenStore = store.NewMemoryTokenStore()
+	manager.MapTokenStorage(tokenStore)
+	manager.MapClientStorage(newClientStore())
+	manager.MapAccessGenerate(&jwtAccessGenerate{keys: keys})
+
+	authServer = server.NewServer(server.NewConfig(), manager)
+	authServer.SetClientInfoHandler(server.ClientFormHandler)
+
+	// PKCE (S256) is required for the public applicant-mobile client; the
+	// library validates code_challenge/code_verifier automatically once a
+	// client has no secret.
+	//
+	// Scope is checked against the client's own registration: a public
+	// client like applicant-mobile must never be able to mint loan:approve
+	// or loan:read-pii just by asking for it in the token request.
+	authServer.SetClientScopeHandler(func(tgr *oauth2.TokenGenerateRequest) (bool, error) {
+		allowed := scopesFor(tgr.ClientID)
+		for _, requested := range splitScope(tgr.Scope) {
+			if !containsScope(allowed, requested) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+
+	authServer.SetInternalErrorHandler(func(err error) *oautherrors.Response {
+		return &oautherrors.Response{Error: err, StatusCode: http.StatusInternalServerError}
+	})
+}
+
+// inMemoryClientStore adapts registeredClients to oauth2.ClientStore.
+type inMemoryClientStore struct {
+	byID map[string]*models.Client
+}
+
+func newClientStore() ClientStore {
+	s := &inMemoryClientStore{byID: make(map[string]*models.Client, len(registeredClients))}
+	for _, rc := range registeredClients {
+		s.byID[rc.ID] = &models.Client{
+			ID:     rc.ID,
+			Secret: rc.Secret,
+			Domain: rc.Domain,
+			Public: rc.Public,
+		}
+	}
+	return s
+}
+
+func (s *inMemoryClientStore) GetByID(ctx context.Context, id string) (oauth2.ClientInfo, error) {
+	c, ok := s.byID[id]
+	if !ok {
+		return nil, oauth2.ErrInvalidClient
+	}
+	return c, nil
+}
+
+// scopesFor returns the scopes a registered client is allowed to request.
+func scopesFor(clientID string) []string {
+	for _, rc := range registeredClients {
+		if rc.ID == clientID {
+			return rc.Scopes
+		}
+	}
+	return nil
+}
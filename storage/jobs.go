@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// JobStatus is the lifecycle of a document extraction job, persisted so a
+// restart doesn't lose track of in-flight uploads.
+type JobStatus string
+
+const (
+	JobQueued       JobStatus = "queued"
+	JobProcessing   JobStatus = "processing"
+	JobCompleted    JobStatus = "completed"
+	JobFailed       JobStatus = "failed"
+	JobDeadLettered JobStatus = "dead_lettered"
+)
+
+// DocumentJobRecord is one queued/processed PDF extraction attempt.
+// ExtractedFields holds the extractor's output as JSON once the job
+// completes; it's a string here (rather than map[string]any) because that's
+// exactly what gets stored and returned, no further processing needed.
+type DocumentJobRecord struct {
+	ID              int
+	ApplicationID   int
+	FilePath        string
+	Status          JobStatus
+	Attempts        int
+	LastError       string
+	ExtractedFields string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// JobRepository is the persistence boundary for the extraction pipeline
+// (see package extraction). It's embedded into Repository so both the
+// loan-application CRUD and the job bookkeeping share one transactional
+// home, the same way the old processingStatus map lived next to
+// loanApplications.
+type JobRepository interface {
+	CreateDocumentJob(ctx context.Context, applicationID int, filePath string) (DocumentJobRecord, error)
+	GetDocumentJob(ctx context.Context, id int) (DocumentJobRecord, error)
+	GetLatestDocumentJobForApplication(ctx context.Context, applicationID int) (DocumentJobRecord, error)
+	MarkJobProcessing(ctx context.Context, id int) error
+	MarkJobCompleted(ctx context.Context, id int, extractedFieldsJSON string) error
+	MarkJobFailed(ctx context.Context, id int, lastErr string) (attempts int, err error)
+	MoveToDeadLetter(ctx context.Context, id int, reason string) error
+}
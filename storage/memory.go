@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryRepository is the in-memory Repository implementation: a direct,
+// mutex-guarded replacement for the old loanApplications/currentID/
+// processingStatus globals. Used in tests and local dev without Postgres.
+type MemoryRepository struct {
+	mu               sync.RWMutex
+	applications     map[int]LoanApplication
+	nextID           int
+	processingStatus map[int]string
+	jobs             map[int]DocumentJobRecord
+	nextJobID        int
+}
+
+// NewMemoryRepository returns an empty, ready-to-use MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		applications:     make(map[int]LoanApplication),
+		nextID:           1,
+		processingStatus: make(map[int]string),
+		jobs:             make(map[int]DocumentJobRecord),
+		nextJobID:        1,
+	}
+}
+
+func (r *MemoryRepository) CreateApplication(ctx context.Context, app LoanApplication) (LoanApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	app.ID = r.nextID
+	r.applications[app.ID] = app
+	r.nextID++
+	return app, nil
+}
+
+func (r *MemoryRepository) GetApplication(ctx context.Context, id int) (LoanApplication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	app, ok := r.applications[id]
+	if !ok {
+		return LoanApplication{}, ErrNotFound
+	}
+	return app, nil
+}
+
+func (r *MemoryRepository) ListApplications(ctx context.Context, filter Filter, page Page) (ListResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status := strings.ToLower(filter.Status)
+	name := strings.ToLower(filter.Name)
+
+	var filtered []LoanApplication
+	for _, app := range r.applications {
+		if status != "" && strings.ToLower(app.Status) != status {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(app.ApplicantName), name) {
+			continue
+		}
+		filtered = append(filtered, app)
+	}
+
+	offset, limit := page.Offset, page.Limit
+	if offset > len(filtered) {
+		offset = len(filtered)
+	}
+	end := offset + limit
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+
+	return ListResult{Total: len(filtered), Results: filtered[offset:end]}, nil
+}
+
+func (r *MemoryRepository) UpdateStatus(ctx context.Context, id int, status string) (LoanApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	app, ok := r.applications[id]
+	if !ok {
+		return LoanApplication{}, ErrNotFound
+	}
+	app.Status = status
+	now := time.Now()
+	app.ProcessedAt = &now
+	r.applications[id] = app
+	return app, nil
+}
+
+func (r *MemoryRepository) AppendDocument(ctx context.Context, id int, filename string) (LoanApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	app, ok := r.applications[id]
+	if !ok {
+		return LoanApplication{}, ErrNotFound
+	}
+	app.DocumentsUploaded = append(app.DocumentsUploaded, filename)
+	r.applications[id] = app
+	return app, nil
+}
+
+func (r *MemoryRepository) SetProcessingStatus(ctx context.Context, id int, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processingStatus[id] = status
+	return nil
+}
+
+func (r *MemoryRepository) GetProcessingStatus(ctx context.Context, id int) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	status, ok := r.processingStatus[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return status, nil
+}
+
+func (r *MemoryRepository) RewrapSSN(ctx context.Context, id int, ciphertext, keyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	app, ok := r.applications[id]
+	if !ok {
+		return ErrNotFound
+	}
+	app.ApplicantSSN = ciphertext
+	app.SSNKeyID = keyID
+	r.applications[id] = app
+	return nil
+}
+
+func (r *MemoryRepository) ListSSNKeyIDs(ctx context.Context) (map[int]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[int]string, len(r.applications))
+	for id, app := range r.applications {
+		out[id] = app.SSNKeyID
+	}
+	return out, nil
+}
+
+// WithTx has nothing to roll back in memory, so it just runs fn against the
+// same repository; the mutex inside each method still serializes access.
+func (r *MemoryRepository) WithTx(ctx context.Context, fn func(ctx context.Context, repo Repository) error) error {
+	return fn(ctx, r)
+}
+
+func (r *MemoryRepository) CreateDocumentJob(ctx context.Context, applicationID int, filePath string) (DocumentJobRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	job := DocumentJobRecord{
+		ID:            r.nextJobID,
+		ApplicationID: applicationID,
+		FilePath:      filePath,
+		Status:        JobQueued,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	r.jobs[job.ID] = job
+	r.nextJobID++
+	return job, nil
+}
+
+func (r *MemoryRepository) GetDocumentJob(ctx context.Context, id int) (DocumentJobRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return DocumentJobRecord{}, ErrNotFound
+	}
+	return job, nil
+}
+
+func (r *MemoryRepository) GetLatestDocumentJobForApplication(ctx context.Context, applicationID int) (DocumentJobRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var latest DocumentJobRecord
+	found := false
+	for _, job := range r.jobs {
+		if job.ApplicationID != applicationID {
+			continue
+		}
+		if !found || job.CreatedAt.After(latest.CreatedAt) {
+			latest = job
+			found = true
+		}
+	}
+	if !found {
+		return DocumentJobRecord{}, ErrNotFound
+	}
+	return latest, nil
+}
+
+func (r *MemoryRepository) MarkJobProcessing(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = JobProcessing
+	job.Attempts++
+	job.UpdatedAt = time.Now()
+	r.jobs[id] = job
+	return nil
+}
+
+func (r *MemoryRepository) MarkJobCompleted(ctx context.Context, id int, extractedFieldsJSON string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = JobCompleted
+	job.ExtractedFields = extractedFieldsJSON
+	job.LastError = ""
+	job.UpdatedAt = time.Now()
+	r.jobs[id] = job
+	return nil
+}
+
+func (r *MemoryRepository) MarkJobFailed(ctx context.Context, id int, lastErr string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	job.Status = JobFailed
+	job.LastError = lastErr
+	job.UpdatedAt = time.Now()
+	r.jobs[id] = job
+	return job.Attempts, nil
+}
+
+func (r *MemoryRepository) MoveToDeadLetter(ctx context.Context, id int, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = JobDeadLettered
+	job.LastError = reason
+	job.UpdatedAt = time.Now()
+	r.jobs[id] = job
+	return nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryRepository_CreateAndGet is table-driven across a couple of
+// application shapes to make sure ID assignment and lookups agree.
+func TestMemoryRepository_CreateAndGet(t *testing.T) {
+	tests := []struct {
+		name string
+		app  LoanApplication
+	}{
+		{name: "basic application", app: LoanApplication{ApplicantName: "Jane Smith", LoanAmount: 20000, Status: "pending"}},
+		{name: "zero amount still stored", app: LoanApplication{ApplicantName: "John Doe", LoanAmount: 0, Status: "pending"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewMemoryRepository()
+			ctx := context.Background()
+
+			created, err := repo.CreateApplication(ctx, tt.app)
+			assert.NoError(t, err)
+			assert.NotZero(t, created.ID)
+
+			got, err := repo.GetApplication(ctx, created.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.app.ApplicantName, got.ApplicantName)
+		})
+	}
+}
+
+func TestMemoryRepository_GetApplication_NotFound(t *testing.T) {
+	repo := NewMemoryRepository()
+	_, err := repo.GetApplication(context.Background(), 999)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryRepository_ListApplications_FiltersAndPaginates(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	names := []string{"Alice Approved", "Bob Approved", "Carol Pending"}
+	statuses := []string{"approved", "approved", "pending"}
+	for i, n := range names {
+		_, err := repo.CreateApplication(ctx, LoanApplication{ApplicantName: n, Status: statuses[i]})
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		page      Page
+		wantTotal int
+		wantLen   int
+	}{
+		{name: "filter by status", filter: Filter{Status: "approved"}, page: Page{Limit: 10}, wantTotal: 2, wantLen: 2},
+		{name: "filter by name substring", filter: Filter{Name: "carol"}, page: Page{Limit: 10}, wantTotal: 1, wantLen: 1},
+		{name: "paginate", filter: Filter{}, page: Page{Limit: 1, Offset: 1}, wantTotal: 3, wantLen: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.ListApplications(ctx, tt.filter, tt.page)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, result.Total)
+			assert.Len(t, result.Results, tt.wantLen)
+		})
+	}
+}
+
+func TestMemoryRepository_UpdateStatus(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	created, _ := repo.CreateApplication(ctx, LoanApplication{ApplicantName: "Jane Smith", Status: "pending"})
+
+	updated, err := repo.UpdateStatus(ctx, created.ID, "approved")
+	assert.NoError(t, err)
+	assert.Equal(t, "approved", updated.Status)
+	assert.NotNil(t, updated.ProcessedAt)
+
+	_, err = repo.UpdateStatus(ctx, 999, "approved")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryRepository_ProcessingStatus(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	_, err := repo.GetProcessingStatus(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, repo.SetProcessingStatus(ctx, 1, "queued"))
+	status, err := repo.GetProcessingStatus(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "queued", status)
+}
@@ -0,0 +1,436 @@
+package storage
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migrations live here (storage/migrations) rather than the top-level
+// db/migrations a schema-first reader might expect, because go:embed can
+// only reach files under the package directory it's declared in - it can't
+// walk a "../db/migrations" path. Ops tooling that looks for db/migrations
+// directly on disk (rather than going through Migrate/this embed.FS) needs
+// pointing at storage/migrations instead.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// PostgresRepository is the production Repository implementation, backed
+// by a pgx connection pool.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository wraps an already-connected pool. Call Migrate
+// first (or let main do it on startup) so the schema exists.
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// Migrate runs every *.sql file under storage/migrations, in filename
+// order, inside a migrations_applied tracking table so re-running main is
+// a no-op once the schema is current.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS migrations_applied (
+		filename TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("storage: create migrations table: %w", err)
+	}
+
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return fmt.Errorf("storage: read migrations dir: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var applied bool
+		err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM migrations_applied WHERE filename = $1)`, name).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("storage: check migration %s: %w", name, err)
+		}
+		if applied {
+			continue
+		}
+		sqlBytes, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return fmt.Errorf("storage: read migration %s: %w", name, err)
+		}
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("storage: begin migration %s: %w", name, err)
+		}
+		if _, err := tx.Exec(ctx, string(sqlBytes)); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("storage: apply migration %s: %w", name, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO migrations_applied (filename) VALUES ($1)`, name); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("storage: record migration %s: %w", name, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("storage: commit migration %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CreateApplication(ctx context.Context, app LoanApplication) (LoanApplication, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO loan_applications
+			(applicant_name, applicant_ssn, ssn_key_id, masked_ssn, loan_amount, loan_purpose, annual_income, credit_score, status, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`,
+		app.ApplicantName, app.ApplicantSSN, app.SSNKeyID, app.MaskedSSN, app.LoanAmount, app.LoanPurpose, app.AnnualIncome, app.CreditScore, app.Status, app.SubmittedAt)
+	if err := row.Scan(&app.ID); err != nil {
+		return LoanApplication{}, fmt.Errorf("storage: create application: %w", err)
+	}
+	return app, nil
+}
+
+func (r *PostgresRepository) GetApplication(ctx context.Context, id int) (LoanApplication, error) {
+	app, err := scanApplication(r.pool.QueryRow(ctx, `
+		SELECT id, applicant_name, applicant_ssn, ssn_key_id, masked_ssn, loan_amount, loan_purpose, annual_income,
+		       credit_score, status, submitted_at, processed_at, documents_uploaded
+		FROM loan_applications WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return LoanApplication{}, ErrNotFound
+	}
+	if err != nil {
+		return LoanApplication{}, fmt.Errorf("storage: get application: %w", err)
+	}
+	return app, nil
+}
+
+func (r *PostgresRepository) ListApplications(ctx context.Context, filter Filter, page Page) (ListResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, applicant_name, applicant_ssn, ssn_key_id, masked_ssn, loan_amount, loan_purpose, annual_income,
+		       credit_score, status, submitted_at, processed_at, documents_uploaded
+		FROM loan_applications
+		WHERE ($1 = '' OR lower(status) = lower($1))
+		  AND ($2 = '' OR applicant_name ILIKE '%' || $2 || '%')
+		ORDER BY id
+		LIMIT $3 OFFSET $4`,
+		filter.Status, filter.Name, page.Limit, page.Offset)
+	if err != nil {
+		return ListResult{}, fmt.Errorf("storage: list applications: %w", err)
+	}
+	defer rows.Close()
+
+	var results []LoanApplication
+	for rows.Next() {
+		app, err := scanApplication(rows)
+		if err != nil {
+			return ListResult{}, fmt.Errorf("storage: scan application: %w", err)
+		}
+		results = append(results, app)
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT count(*) FROM loan_applications
+		WHERE ($1 = '' OR lower(status) = lower($1))
+		  AND ($2 = '' OR applicant_name ILIKE '%' || $2 || '%')`,
+		filter.Status, filter.Name).Scan(&total); err != nil {
+		return ListResult{}, fmt.Errorf("storage: count applications: %w", err)
+	}
+
+	return ListResult{Total: total, Results: results}, nil
+}
+
+func (r *PostgresRepository) UpdateStatus(ctx context.Context, id int, status string) (LoanApplication, error) {
+	now := time.Now()
+	tag, err := r.pool.Exec(ctx, `UPDATE loan_applications SET status = $1, processed_at = $2 WHERE id = $3`, status, now, id)
+	if err != nil {
+		return LoanApplication{}, fmt.Errorf("storage: update status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return LoanApplication{}, ErrNotFound
+	}
+	return r.GetApplication(ctx, id)
+}
+
+func (r *PostgresRepository) AppendDocument(ctx context.Context, id int, filename string) (LoanApplication, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE loan_applications
+		SET documents_uploaded = array_append(documents_uploaded, $1)
+		WHERE id = $2`, filename, id)
+	if err != nil {
+		return LoanApplication{}, fmt.Errorf("storage: append document: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return LoanApplication{}, ErrNotFound
+	}
+	return r.GetApplication(ctx, id)
+}
+
+func (r *PostgresRepository) SetProcessingStatus(ctx context.Context, id int, status string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO processing_status (application_id, status, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (application_id) DO UPDATE SET status = $2, updated_at = now()`, id, status)
+	if err != nil {
+		return fmt.Errorf("storage: set processing status: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetProcessingStatus(ctx context.Context, id int) (string, error) {
+	var status string
+	err := r.pool.QueryRow(ctx, `SELECT status FROM processing_status WHERE application_id = $1`, id).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("storage: get processing status: %w", err)
+	}
+	return status, nil
+}
+
+func (r *PostgresRepository) RewrapSSN(ctx context.Context, id int, ciphertext, keyID string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE loan_applications SET applicant_ssn = $1, ssn_key_id = $2 WHERE id = $3`, ciphertext, keyID, id)
+	if err != nil {
+		return fmt.Errorf("storage: rewrap ssn: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListSSNKeyIDs(ctx context.Context) (map[int]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, ssn_key_id FROM loan_applications`)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list ssn key ids: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var keyID string
+		if err := rows.Scan(&id, &keyID); err != nil {
+			return nil, fmt.Errorf("storage: scan ssn key id: %w", err)
+		}
+		out[id] = keyID
+	}
+	return out, nil
+}
+
+// WithTx runs fn against a PostgresRepository scoped to a single pgx
+// transaction, committing on success and rolling back on error or panic.
+func (r *PostgresRepository) WithTx(ctx context.Context, fn func(ctx context.Context, repo Repository) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("storage: begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(ctx, &txQuerierRepository{tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// txQuerierRepository reuses PostgresRepository's SQL against a live
+// transaction instead of the pool, so WithTx callers get real atomicity.
+type txQuerierRepository struct {
+	tx pgx.Tx
+}
+
+func (r *txQuerierRepository) CreateApplication(ctx context.Context, app LoanApplication) (LoanApplication, error) {
+	row := r.tx.QueryRow(ctx, `
+		INSERT INTO loan_applications
+			(applicant_name, applicant_ssn, ssn_key_id, masked_ssn, loan_amount, loan_purpose, annual_income, credit_score, status, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`,
+		app.ApplicantName, app.ApplicantSSN, app.SSNKeyID, app.MaskedSSN, app.LoanAmount, app.LoanPurpose, app.AnnualIncome, app.CreditScore, app.Status, app.SubmittedAt)
+	if err := row.Scan(&app.ID); err != nil {
+		return LoanApplication{}, fmt.Errorf("storage: create application: %w", err)
+	}
+	return app, nil
+}
+
+func (r *txQuerierRepository) GetApplication(ctx context.Context, id int) (LoanApplication, error) {
+	app, err := scanApplication(r.tx.QueryRow(ctx, `
+		SELECT id, applicant_name, applicant_ssn, ssn_key_id, masked_ssn, loan_amount, loan_purpose, annual_income,
+		       credit_score, status, submitted_at, processed_at, documents_uploaded
+		FROM loan_applications WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return LoanApplication{}, ErrNotFound
+	}
+	return app, err
+}
+
+func (r *txQuerierRepository) ListApplications(ctx context.Context, filter Filter, page Page) (ListResult, error) {
+	return ListResult{}, fmt.Errorf("storage: ListApplications is not supported inside a transaction")
+}
+
+func (r *txQuerierRepository) UpdateStatus(ctx context.Context, id int, status string) (LoanApplication, error) {
+	tag, err := r.tx.Exec(ctx, `UPDATE loan_applications SET status = $1, processed_at = now() WHERE id = $2`, status, id)
+	if err != nil {
+		return LoanApplication{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return LoanApplication{}, ErrNotFound
+	}
+	return r.GetApplication(ctx, id)
+}
+
+func (r *txQuerierRepository) AppendDocument(ctx context.Context, id int, filename string) (LoanApplication, error) {
+	tag, err := r.tx.Exec(ctx, `UPDATE loan_applications SET documents_uploaded = array_append(documents_uploaded, $1) WHERE id = $2`, filename, id)
+	if err != nil {
+		return LoanApplication{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return LoanApplication{}, ErrNotFound
+	}
+	return r.GetApplication(ctx, id)
+}
+
+func (r *txQuerierRepository) SetProcessingStatus(ctx context.Context, id int, status string) error {
+	_, err := r.tx.Exec(ctx, `
+		INSERT INTO processing_status (application_id, status, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (application_id) DO UPDATE SET status = $2, updated_at = now()`, id, status)
+	return err
+}
+
+func (r *txQuerierRepository) GetProcessingStatus(ctx context.Context, id int) (string, error) {
+	var status string
+	err := r.tx.QueryRow(ctx, `SELECT status FROM processing_status WHERE application_id = $1`, id).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return "", ErrNotFound
+	}
+	return status, err
+}
+
+func (r *txQuerierRepository) WithTx(ctx context.Context, fn func(ctx context.Context, repo Repository) error) error {
+	return fn(ctx, r) // already inside a transaction; nesting just reuses it
+}
+
+func (r *txQuerierRepository) RewrapSSN(ctx context.Context, id int, ciphertext, keyID string) error {
+	tag, err := r.tx.Exec(ctx, `UPDATE loan_applications SET applicant_ssn = $1, ssn_key_id = $2 WHERE id = $3`, ciphertext, keyID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *txQuerierRepository) ListSSNKeyIDs(ctx context.Context) (map[int]string, error) {
+	rows, err := r.tx.Query(ctx, `SELECT id, ssn_key_id FROM loan_applications`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var keyID string
+		if err := rows.Scan(&id, &keyID); err != nil {
+			return nil, err
+		}
+		out[id] = keyID
+	}
+	return out, nil
+}
+
+func (r *txQuerierRepository) CreateDocumentJob(ctx context.Context, applicationID int, filePath string) (DocumentJobRecord, error) {
+	var job DocumentJobRecord
+	err := r.tx.QueryRow(ctx, `
+		INSERT INTO document_jobs (application_id, file_path, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, application_id, file_path, status, attempts, last_error, extracted_fields, created_at, updated_at`,
+		applicationID, filePath, JobQueued).Scan(
+		&job.ID, &job.ApplicationID, &job.FilePath, &job.Status, &job.Attempts, &job.LastError, &job.ExtractedFields, &job.CreatedAt, &job.UpdatedAt)
+	return job, err
+}
+
+func (r *txQuerierRepository) GetDocumentJob(ctx context.Context, id int) (DocumentJobRecord, error) {
+	return scanDocumentJob(r.tx.QueryRow(ctx, `
+		SELECT id, application_id, file_path, status, attempts, last_error, extracted_fields, created_at, updated_at
+		FROM document_jobs WHERE id = $1`, id))
+}
+
+func (r *txQuerierRepository) GetLatestDocumentJobForApplication(ctx context.Context, applicationID int) (DocumentJobRecord, error) {
+	return scanDocumentJob(r.tx.QueryRow(ctx, `
+		SELECT id, application_id, file_path, status, attempts, last_error, extracted_fields, created_at, updated_at
+		FROM document_jobs WHERE application_id = $1 ORDER BY created_at DESC LIMIT 1`, applicationID))
+}
+
+func (r *txQuerierRepository) MarkJobProcessing(ctx context.Context, id int) error {
+	tag, err := r.tx.Exec(ctx, `UPDATE document_jobs SET status = $1, attempts = attempts + 1, updated_at = now() WHERE id = $2`, JobProcessing, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *txQuerierRepository) MarkJobCompleted(ctx context.Context, id int, extractedFieldsJSON string) error {
+	tag, err := r.tx.Exec(ctx, `UPDATE document_jobs SET status = $1, extracted_fields = $2, last_error = '', updated_at = now() WHERE id = $3`,
+		JobCompleted, extractedFieldsJSON, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *txQuerierRepository) MarkJobFailed(ctx context.Context, id int, lastErr string) (int, error) {
+	var attempts int
+	err := r.tx.QueryRow(ctx, `
+		UPDATE document_jobs SET status = $1, last_error = $2, updated_at = now() WHERE id = $3 RETURNING attempts`,
+		JobFailed, lastErr, id).Scan(&attempts)
+	if err == pgx.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	return attempts, err
+}
+
+func (r *txQuerierRepository) MoveToDeadLetter(ctx context.Context, id int, reason string) error {
+	tag, err := r.tx.Exec(ctx, `UPDATE document_jobs SET status = $1, last_error = $2, updated_at = now() WHERE id = $3`, JobDeadLettered, reason, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both pgx.Row (QueryRow) and pgx.Rows (Query),
+// letting GetApplication/ListApplications/txQuerierRepository share one
+// scan routine.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanApplication(row rowScanner) (LoanApplication, error) {
+	var app LoanApplication
+	err := row.Scan(
+		&app.ID, &app.ApplicantName, &app.ApplicantSSN, &app.SSNKeyID, &app.MaskedSSN, &app.LoanAmount, &app.LoanPurpose,
+		&app.AnnualIncome, &app.CreditScore, &app.Status, &app.SubmittedAt, &app.ProcessedAt, &app.DocumentsUploaded,
+	)
+	return app, err
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *PostgresRepository) CreateDocumentJob(ctx context.Context, applicationID int, filePath string) (DocumentJobRecord, error) {
+	var job DocumentJobRecord
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO document_jobs (application_id, file_path, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, application_id, file_path, status, attempts, last_error, extracted_fields, created_at, updated_at`,
+		applicationID, filePath, JobQueued).Scan(
+		&job.ID, &job.ApplicationID, &job.FilePath, &job.Status, &job.Attempts, &job.LastError, &job.ExtractedFields, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return DocumentJobRecord{}, fmt.Errorf("storage: create document job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *PostgresRepository) GetDocumentJob(ctx context.Context, id int) (DocumentJobRecord, error) {
+	return scanDocumentJob(r.pool.QueryRow(ctx, `
+		SELECT id, application_id, file_path, status, attempts, last_error, extracted_fields, created_at, updated_at
+		FROM document_jobs WHERE id = $1`, id))
+}
+
+func (r *PostgresRepository) GetLatestDocumentJobForApplication(ctx context.Context, applicationID int) (DocumentJobRecord, error) {
+	return scanDocumentJob(r.pool.QueryRow(ctx, `
+		SELECT id, application_id, file_path, status, attempts, last_error, extracted_fields, created_at, updated_at
+		FROM document_jobs WHERE application_id = $1 ORDER BY created_at DESC LIMIT 1`, applicationID))
+}
+
+func (r *PostgresRepository) MarkJobProcessing(ctx context.Context, id int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE document_jobs SET status = $1, attempts = attempts + 1, updated_at = now() WHERE id = $2`,
+		JobProcessing, id)
+	if err != nil {
+		return fmt.Errorf("storage: mark job processing: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) MarkJobCompleted(ctx context.Context, id int, extractedFieldsJSON string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE document_jobs SET status = $1, extracted_fields = $2, last_error = '', updated_at = now() WHERE id = $3`,
+		JobCompleted, extractedFieldsJSON, id)
+	if err != nil {
+		return fmt.Errorf("storage: mark job completed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) MarkJobFailed(ctx context.Context, id int, lastErr string) (int, error) {
+	var attempts int
+	err := r.pool.QueryRow(ctx, `
+		UPDATE document_jobs SET status = $1, last_error = $2, updated_at = now()
+		WHERE id = $3
+		RETURNING attempts`, JobFailed, lastErr, id).Scan(&attempts)
+	if err == pgx.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("storage: mark job failed: %w", err)
+	}
+	return attempts, nil
+}
+
+func (r *PostgresRepository) MoveToDeadLetter(ctx context.Context, id int, reason string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE document_jobs SET status = $1, last_error = $2, updated_at = now() WHERE id = $3`,
+		JobDeadLettered, reason, id)
+	if err != nil {
+		return fmt.Errorf("storage: move job to dead letter: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanDocumentJob(row rowScanner) (DocumentJobRecord, error) {
+	var job DocumentJobRecord
+	err := row.Scan(&job.ID, &job.ApplicationID, &job.FilePath, &job.Status, &job.Attempts, &job.LastError, &job.ExtractedFields, &job.CreatedAt, &job.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return DocumentJobRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return DocumentJobRecord{}, fmt.Errorf("storage: scan document job: %w", err)
+	}
+	return job, nil
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostgresRepository_CreateAndGet runs the same contract as
+// TestMemoryRepository_CreateAndGet against a real Postgres instance. It's
+// skipped unless TEST_DATABASE_URL is set, since this repo doesn't run a
+// database in CI by default.
+func TestPostgresRepository_CreateAndGet(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping Postgres-backed repository test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	assert.NoError(t, err)
+	defer pool.Close()
+
+	assert.NoError(t, Migrate(ctx, pool))
+
+	repo := NewPostgresRepository(pool)
+	created, err := repo.CreateApplication(ctx, LoanApplication{ApplicantName: "Jane Smith", LoanAmount: 20000, Status: "pending"})
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	got, err := repo.GetApplication(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Smith", got.ApplicantName)
+}
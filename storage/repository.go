@@ -0,0 +1,90 @@
+// Package storage defines the persistence boundary for loan applications.
+// It replaces the package-level loanApplications/currentID/processingStatus
+// globals main.go used to mutate directly: handlers now talk to a
+// Repository, which can be the in-memory implementation (tests, local dev)
+// or the Postgres-backed one (production).
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Repository methods when the requested
+// application doesn't exist. Handlers translate it to apierror.ErrNotFound.
+var ErrNotFound = errors.New("storage: application not found")
+
+// LoanApplication mirrors the JSON shape the API has always returned; it
+// now lives here instead of main.go so the repository and its
+// implementations can depend on it without an import cycle.
+type LoanApplication struct {
+	ID            int    `json:"id"`
+	ApplicantName string `json:"applicant_name"`
+	// ApplicantSSN holds the envelope-encrypted ciphertext at rest (see
+	// package crypto), never plaintext; SSNKeyID is the key it was sealed
+	// under. Handlers are the only thing that should touch either field
+	// directly - they decrypt for loan:read-pii callers and otherwise clear
+	// both before a response goes out, leaving just MaskedSSN.
+	ApplicantSSN      string     `json:"applicant_ssn,omitempty"`
+	SSNKeyID          string     `json:"-"`
+	MaskedSSN         string     `json:"masked_ssn,omitempty"`
+	LoanAmount        float64    `json:"loan_amount"`
+	LoanPurpose       string     `json:"loan_purpose"`
+	AnnualIncome      float64    `json:"annual_income"`
+	CreditScore       int        `json:"credit_score"`
+	Status            string     `json:"status"`
+	SubmittedAt       time.Time  `json:"submitted_at"`
+	ProcessedAt       *time.Time `json:"processed_at,omitempty"`
+	DocumentsUploaded []string   `json:"documents_uploaded"`
+}
+
+// Filter narrows ListApplications the same way GetLoanApplications' query
+// params always have: by status and a case-insensitive name substring.
+type Filter struct {
+	Status string
+	Name   string
+}
+
+// Page is a simple limit/offset pair, matching the ?limit=&offset= params
+// GetLoanApplications already accepted.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// ListResult carries both the page of results and the total matching count,
+// so handlers can keep returning {total, limit, offset, results}.
+type ListResult struct {
+	Total   int
+	Results []LoanApplication
+}
+
+// Repository is the persistence boundary every handler goes through instead
+// of touching maps directly. CreateApplication assigns the ID; all other
+// methods operate on an existing one.
+type Repository interface {
+	CreateApplication(ctx context.Context, app LoanApplication) (LoanApplication, error)
+	GetApplication(ctx context.Context, id int) (LoanApplication, error)
+	ListApplications(ctx context.Context, filter Filter, page Page) (ListResult, error)
+	UpdateStatus(ctx context.Context, id int, status string) (LoanApplication, error)
+	AppendDocument(ctx context.Context, id int, filename string) (LoanApplication, error)
+	SetProcessingStatus(ctx context.Context, id int, status string) error
+	GetProcessingStatus(ctx context.Context, id int) (string, error)
+
+	// RewrapSSN overwrites an application's encrypted SSN in place with a
+	// ciphertext sealed under a different key - used by crypto.RewrapJob
+	// after a KEK rotation. It does not touch MaskedSSN.
+	RewrapSSN(ctx context.Context, id int, ciphertext, keyID string) error
+	// ListSSNKeyIDs returns the key ID every application's SSN is currently
+	// sealed under, so the rewrap job can find records still on an old key.
+	ListSSNKeyIDs(ctx context.Context) (map[int]string, error)
+
+	// WithTx runs fn against a Repository scoped to a single transaction;
+	// implementations that don't support transactions (the in-memory one)
+	// just run fn against themselves, with no lock held across fn; each
+	// method call inside it still takes the implementation's own lock.
+	WithTx(ctx context.Context, fn func(ctx context.Context, repo Repository) error) error
+
+	JobRepository
+}
@@ -0,0 +1,118 @@
+// Package streaming gives clients a way to watch a loan application's
+// processing status change in real time instead of polling
+// GetProcessingStatus. It's an in-process pub/sub keyed by application ID,
+// with a bounded per-application history so a reconnecting client can
+// replay whatever it missed via Last-Event-ID.
+//
+// That history is in memory only: it doesn't survive a restart, and it's
+// not shared across replicas behind a load balancer. Last-Event-ID replay
+// is therefore best-effort, bounded to this process's lifetime - a client
+// reconnecting after a deploy or restart gets no replay and should fall
+// back to GetProcessingStatus to resync before trusting the event stream.
+package streaming
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event is one state change pushed to subscribers of an application's
+// topic: a status transition, an extraction progress tick, or the
+// terminal result.
+type Event struct {
+	ID            int64     `json:"id"`
+	ApplicationID int       `json:"application_id"`
+	Type          string    `json:"type"` // "status" | "progress" | "result"
+	Status        string    `json:"status"`
+	Progress      int       `json:"progress,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// historyLimit bounds how many past events each topic keeps around for
+// Last-Event-ID replay; old jobs don't need to replay forever.
+const historyLimit = 50
+
+// Hub is the pub/sub core: one topic (channel fan-out) per application ID.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan Event]struct{}
+	history     map[int][]Event
+	nextID      atomic.Int64
+}
+
+// NewHub returns an empty, ready-to-use Hub.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[int]map[chan Event]struct{}),
+		history:     make(map[int][]Event),
+	}
+}
+
+// Publish implements extraction.Publisher: it fans the event out to every
+// current subscriber of applicationID and appends it to that topic's
+// bounded history.
+func (h *Hub) Publish(applicationID int, eventType, status string, progress int) {
+	evt := Event{
+		ID:            h.nextID.Add(1),
+		ApplicationID: applicationID,
+		Type:          eventType,
+		Status:        status,
+		Progress:      progress,
+		Time:          time.Now(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist := append(h.history[applicationID], evt)
+	if len(hist) > historyLimit {
+		hist = hist[len(hist)-historyLimit:]
+	}
+	h.history[applicationID] = hist
+
+	for ch := range h.subscribers[applicationID] {
+		select {
+		case ch <- evt:
+		default: // slow subscriber; drop rather than block publishing
+		}
+	}
+}
+
+// Subscribe registers a new listener for applicationID's topic. Call the
+// returned unsubscribe func when done (e.g. when the client disconnects).
+func (h *Hub) Subscribe(applicationID int) (ch chan Event, unsubscribe func()) {
+	ch = make(chan Event, 16)
+
+	h.mu.Lock()
+	if h.subscribers[applicationID] == nil {
+		h.subscribers[applicationID] = make(map[chan Event]struct{})
+	}
+	h.subscribers[applicationID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers[applicationID], ch)
+		h.mu.Unlock()
+		close(ch)
+	}
+}
+
+// Replay returns every event after sinceID for applicationID, oldest first,
+// bounded by historyLimit. Used to serve the Last-Event-ID replay on SSE
+// reconnect - see the package doc for why this is in-memory/best-effort
+// rather than backed by the repository: history is a map, not a persisted
+// log, so it's always empty right after this process starts.
+func (h *Hub) Replay(applicationID int, sinceID int64) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var out []Event
+	for _, evt := range h.history[applicationID] {
+		if evt.ID > sinceID {
+			out = append(out, evt)
+		}
+	}
+	return out
+}
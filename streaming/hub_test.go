@@ -0,0 +1,57 @@
+package streaming
+
+import "testing"
+
+func TestHub_PublishDeliversToSubscriber(t *testing.T) {
+	hub := NewHub()
+	ch, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+
+	hub.Publish(1, "status", "processing", 0)
+
+	evt := <-ch
+	if evt.ApplicationID != 1 || evt.Status != "processing" || evt.Type != "status" {
+		t.Fatalf("unexpected event: %+v", evt)
+	}
+}
+
+func TestHub_PublishDoesNotLeakAcrossApplications(t *testing.T) {
+	hub := NewHub()
+	ch, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+
+	hub.Publish(2, "status", "processing", 0)
+
+	select {
+	case evt := <-ch:
+		t.Fatalf("expected no event for application 1, got %+v", evt)
+	default:
+	}
+}
+
+func TestHub_ReplayReturnsEventsAfterSinceID(t *testing.T) {
+	hub := NewHub()
+	hub.Publish(1, "status", "processing", 0)
+	hub.Publish(1, "progress", "processing", 50)
+	hub.Publish(1, "result", "completed", 100)
+
+	replayed := hub.Replay(1, 1)
+	if len(replayed) != 2 {
+		t.Fatalf("expected 2 events after id 1, got %d", len(replayed))
+	}
+	if replayed[0].Type != "progress" || replayed[1].Type != "result" {
+		t.Fatalf("unexpected replay order: %+v", replayed)
+	}
+}
+
+func TestHub_ReplayTrimsToHistoryLimit(t *testing.T) {
+	hub := NewHub()
+	for i := 0; i < historyLimit+10; i++ {
+		hub.Publish(1, "progress", "processing", i)
+	}
+
+	replayed := hub.Replay(1, 0)
+	if len(replayed) != historyLimit {
+		t.Fatalf("expected history trimmed to %d, got %d", historyLimit, len(replayed))
+	}
+}
@@ -0,0 +1,85 @@
+package streaming
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+// SSEHandler returns a gin handler for GET /loan-applications/:id/events.
+// It streams status transitions, progress ticks, and the terminal result as
+// Server-Sent Events, replaying anything the client missed if it reconnects
+// with Last-Event-ID set. Replay is served from the Hub's in-memory history
+// (see the streaming package doc), not the repository, so it's best-effort
+// and bounded to this process's lifetime - a client reconnecting after a
+// restart gets no replay and should resync via GetProcessingStatus.
+func SSEHandler(hub *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, _ := strconv.Atoi(c.Param("id"))
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+
+		flusher, ok := c.Writer.(http.Flusher)
+		if !ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if lastEventID := lastEventID(c); lastEventID > 0 {
+			for _, evt := range hub.Replay(appID, lastEventID) {
+				writeEvent(c, evt)
+			}
+			flusher.Flush()
+		}
+
+		ch, unsubscribe := hub.Subscribe(appID)
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case evt := <-ch:
+				writeEvent(c, evt)
+				flusher.Flush()
+				if evt.Type == "result" {
+					return
+				}
+			case <-heartbeat.C:
+				fmt.Fprint(c.Writer, ": ping\n\n")
+				flusher.Flush()
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// lastEventID reads the standard Last-Event-ID header, falling back to a
+// query param of the same name since the EventSource polyfills some
+// clients use can't set custom headers on the initial request.
+func lastEventID(c *gin.Context) int64 {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	id, _ := strconv.ParseInt(raw, 10, 64)
+	return id
+}
+
+func writeEvent(c *gin.Context, evt Event) {
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", evt.ID, evt.Type, eventJSON(evt))
+}
+
+func eventJSON(evt Event) string {
+	return fmt.Sprintf(`{"application_id":%d,"status":%q,"progress":%d,"time":%q}`,
+		evt.ApplicationID, evt.Status, evt.Progress, evt.Time.Format(time.RFC3339))
+}
@@ -0,0 +1,76 @@
+package streaming
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	// No origin check: the handshake is accepted from any origin. The
+	// request must still carry a valid token for the auth middleware in
+	// front of this handler.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// WSHandler returns a gin handler for GET /ws?application_id=:id. It upgrades
+// to a bidirectional websocket and pushes the same Events an SSE client would
+// receive; clients can send any message to keep the connection alive but
+// nothing they send is otherwise interpreted.
+func WSHandler(hub *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, err := strconv.Atoi(c.Query("application_id"))
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		ch, unsubscribe := hub.Subscribe(appID)
+		defer unsubscribe()
+
+		// Drain and discard client reads so we notice the socket closing:
+		// the first read error closes the connection. No read deadline is
+		// set.
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case evt, ok := <-ch:
+				if !ok {
+					return
+				}
+				if err := conn.WriteJSON(evt); err != nil {
+					return
+				}
+				if evt.Type == "result" {
+					return
+				}
+			case <-heartbeat.C:
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Package tracing wires up OpenTelemetry: a tracer provider exporting spans
+// over OTLP when configured, and the W3C traceparent propagator every
+// request and background job context carries spans through.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serviceName = "loanapi"
+
+// Init configures the global tracer provider and propagator. If
+// OTEL_EXPORTER_OTLP_ENDPOINT is unset, spans are still created (so call
+// sites don't need to branch) but go nowhere - the SDK's default no-op
+// behavior for an unconfigured exporter - which keeps local dev and tests
+// working without a collector running. The returned shutdown func should
+// be deferred so buffered spans flush on exit.
+func Init(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		tp := sdktrace.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+		return tp.Shutdown, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer is the one tracer every package in this service starts spans
+// from.
+func Tracer() trace.Tracer {
+	return otel.Tracer(serviceName)
+}
+
+// Propagator returns the configured text-map propagator, used to inject a
+// traceparent header when handing work off to the extraction pool and to
+// extract it back out on the worker side.
+func Propagator() propagation.TextMapPropagator {
+	return otel.GetTextMapPropagator()
+}